@@ -0,0 +1,228 @@
+package chaos_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/chaos"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/fake/faketest"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+)
+
+// sixNodes returns a network of six nodes, of which node1 to node5 are validators
+func sixNodes() *network.Network {
+	networkCfg := &network.Network{}
+	for i := 1; i <= 6; i++ {
+		networkCfg.Nodes = append(networkCfg.Nodes, &node.BaseNode{ID: fmt.Sprintf("node%d", i)})
+	}
+	return networkCfg
+}
+
+func TestScheduleIsSeeded(t *testing.T) {
+	cfg := chaos.Config{Seed: 42, Duration: 10 * time.Minute}
+
+	first, err := chaos.NewSchedule(cfg, sixNodes())
+	require.NoError(t, err)
+	second, err := chaos.NewSchedule(cfg, sixNodes())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.NotEmpty(t, first.Events)
+
+	cfg.Seed = 43
+	other, err := chaos.NewSchedule(cfg, sixNodes())
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Events, other.Events)
+}
+
+func TestScheduleConstraints(t *testing.T) {
+	validators := []string{"node1", "node2", "node3", "node4", "node5"}
+
+	for seed := int64(0); seed < 50; seed++ {
+		schedule, err := chaos.NewSchedule(chaos.Config{
+			Seed:       seed,
+			Duration:   10 * time.Minute,
+			Validators: validators,
+		}, sixNodes())
+		require.NoError(t, err)
+
+		// Replay the schedule against the constraints: a third of 5 validators is 1
+		faults := make(map[string]chaos.Action)
+		var minority []string
+		for i, event := range schedule.Events {
+			if i > 0 {
+				require.GreaterOrEqual(t, event.At, schedule.Events[i-1].At)
+			}
+			switch event.Action {
+			case chaos.ActionKill, chaos.ActionPause, chaos.ActionDegrade:
+				require.Empty(t, faults[event.Node], "node %s has two faults", event.Node)
+				faults[event.Node] = event.Action
+			case chaos.ActionStart, chaos.ActionResume, chaos.ActionRestore:
+				require.NotEmpty(t, faults[event.Node])
+				delete(faults, event.Node)
+			case chaos.ActionPartition:
+				require.Nil(t, minority)
+				for _, fault := range faults {
+					require.NotEqual(t, chaos.ActionKill, fault)
+				}
+				require.Len(t, event.Groups, 2)
+				minority = event.Groups[1]
+			case chaos.ActionHeal:
+				minority = nil
+			}
+
+			down := 0
+			for _, id := range validators {
+				if faults[id] == chaos.ActionKill || faults[id] == chaos.ActionPause || slices.Contains(minority, id) {
+					down++
+				}
+			}
+			require.LessOrEqual(t, down, 1, "seed %d event %d", seed, i)
+		}
+
+		// Every fault is recovered by the end of the run
+		assert.Empty(t, faults)
+		assert.Nil(t, minority)
+		assert.LessOrEqual(t, schedule.Events[len(schedule.Events)-1].At, 10*time.Minute)
+	}
+}
+
+func TestScheduleMaxDownAndSupports(t *testing.T) {
+	// No validator may go down, only the non-validator node6 takes faults and links can still degrade
+	maxDown := 0
+	schedule, err := chaos.NewSchedule(chaos.Config{
+		Seed:       1,
+		Duration:   10 * time.Minute,
+		Validators: []string{"node1", "node2", "node3", "node4", "node5"},
+		MaxDown:    &maxDown,
+	}, sixNodes())
+	require.NoError(t, err)
+	require.NotEmpty(t, schedule.Events)
+	for _, event := range schedule.Events {
+		if event.Action != chaos.ActionDegrade && event.Action != chaos.ActionRestore {
+			assert.Equal(t, "node6", event.Node, "%s of a validator with MaxDown 0", event.Action)
+		}
+		assert.NotEqual(t, chaos.ActionPartition, event.Action)
+	}
+
+	// Faults the environment cannot inject are left out of the defaults
+	onlyPause := func(capability environments.Capability) bool { return capability == environments.CapabilityPause }
+	schedule, err = chaos.NewSchedule(chaos.Config{Seed: 1, Duration: 10 * time.Minute, Supports: onlyPause}, sixNodes())
+	require.NoError(t, err)
+	for _, event := range schedule.Events {
+		assert.NotContains(t, []chaos.Action{chaos.ActionPartition, chaos.ActionDegrade}, event.Action)
+	}
+
+	// and cannot be asked for explicitly
+	_, err = chaos.NewSchedule(chaos.Config{
+		Duration: time.Minute,
+		Faults:   []chaos.Action{chaos.ActionPartition},
+		Supports: onlyPause,
+	}, sixNodes())
+	assert.ErrorContains(t, err, `fault "partition" needs an environment with the partition capability`)
+}
+
+func TestScheduleErrors(t *testing.T) {
+	_, err := chaos.NewSchedule(chaos.Config{}, sixNodes())
+	assert.ErrorContains(t, err, "duration must be positive")
+
+	_, err = chaos.NewSchedule(chaos.Config{Duration: time.Minute, Faults: []chaos.Action{"meteor"}}, sixNodes())
+	assert.ErrorContains(t, err, `unknown fault "meteor"`)
+
+	_, err = chaos.NewSchedule(chaos.Config{Duration: time.Minute, Validators: []string{"node9"}}, sixNodes())
+	assert.ErrorContains(t, err, "unknown validator node9")
+}
+
+// recordingTarget records the calls made by a run
+type recordingTarget struct {
+	calls []string
+	fail  string
+}
+
+func (r *recordingTarget) call(call string) error {
+	r.calls = append(r.calls, call)
+	if call == r.fail {
+		return fmt.Errorf("injected failure")
+	}
+	return nil
+}
+
+func (r *recordingTarget) StopNode(id string) error   { return r.call("stop " + id) }
+func (r *recordingTarget) StartNode(id string) error  { return r.call("start " + id) }
+func (r *recordingTarget) PauseNode(id string) error  { return r.call("pause " + id) }
+func (r *recordingTarget) ResumeNode(id string) error { return r.call("resume " + id) }
+func (r *recordingTarget) Heal() error                { return r.call("heal") }
+func (r *recordingTarget) Partition(groups ...[]string) error {
+	return r.call(fmt.Sprintf("partition %v", groups))
+}
+func (r *recordingTarget) ShapeLinks(shapes ...network.LinkShape) error {
+	var nodes []string
+	for _, shape := range shapes {
+		nodes = append(nodes, shape.Node)
+	}
+	return r.call(fmt.Sprintf("shape %v", nodes))
+}
+
+func TestRunAndReplay(t *testing.T) {
+	profile := network.LinkProfile{Delay: time.Millisecond}
+	schedule := &chaos.Schedule{Seed: 7, Events: []chaos.Event{
+		{At: 0, Action: chaos.ActionKill, Node: "node1"},
+		{At: time.Millisecond, Action: chaos.ActionDegrade, Node: "node2", Profile: &profile},
+		{At: time.Millisecond, Action: chaos.ActionDegrade, Node: "node3", Profile: &profile},
+		{At: 2 * time.Millisecond, Action: chaos.ActionRestore, Node: "node2"},
+		{At: 2 * time.Millisecond, Action: chaos.ActionStart, Node: "node1"},
+		{At: 3 * time.Millisecond, Action: chaos.ActionRestart, Node: "node4"},
+		{At: 3 * time.Millisecond, Action: chaos.ActionPartition, Groups: [][]string{{"node1", "node2"}, {"node3"}}},
+		{At: 4 * time.Millisecond, Action: chaos.ActionHeal},
+	}}
+
+	target := &recordingTarget{}
+	timeline, err := schedule.Run(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"stop node1", "shape [node2]", "shape [node2 node3]", "shape [node3]", "start node1",
+		"stop node4", "start node4", "partition [[node1 node2] [node3]]", "heal",
+	}, target.calls)
+
+	// The stored timeline replays the same fault sequence
+	data, err := json.Marshal(timeline)
+	require.NoError(t, err)
+	var stored chaos.Timeline
+	require.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, schedule, stored.Schedule())
+
+	// A failing event stops the run and is recorded
+	failing := &recordingTarget{fail: "start node1"}
+	timeline, err = schedule.Run(context.Background(), failing)
+	require.ErrorContains(t, err, "chaos start at 2ms failed: injected failure")
+	require.Len(t, timeline.Records, 5)
+	assert.Equal(t, "injected failure", timeline.Records[4].Error)
+}
+
+func TestRunOnFakeNetwork(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	c, _ := faketest.StartClient(t, networkCfg)
+
+	schedule, err := chaos.NewSchedule(chaos.Config{
+		Seed:     1,
+		Duration: 200 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+		Supports: c.Supports, // the fake environment cannot shape links
+	}, networkCfg)
+	require.NoError(t, err)
+
+	timeline, err := schedule.Run(context.Background(), c)
+	require.NoError(t, err)
+	assert.Len(t, timeline.Records, len(schedule.Events))
+
+	// Every fault is recovered, so the network is whole again
+	require.NoError(t, networkCfg.HealthCheck(0, 100*time.Millisecond))
+}
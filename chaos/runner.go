@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/vechain/networkhub/network"
+)
+
+// Target is the running network faults are injected into, *client.Client implements it
+type Target interface {
+	StopNode(nodeID string) error
+	StartNode(nodeID string) error
+	PauseNode(nodeID string) error
+	ResumeNode(nodeID string) error
+	Partition(groups ...[]string) error
+	Heal() error
+	ShapeLinks(shapes ...network.LinkShape) error
+}
+
+// Record is an event as it was executed
+type Record struct {
+	Event
+	Time  time.Time `json:"time"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Timeline records what a run did, it can be stored as JSON and replayed with Schedule
+type Timeline struct {
+	Seed    int64    `json:"seed"`
+	Records []Record `json:"records"`
+}
+
+// Schedule returns the schedule of the recorded events, to replay the exact fault sequence
+func (t *Timeline) Schedule() *Schedule {
+	schedule := &Schedule{Seed: t.Seed}
+	for _, record := range t.Records {
+		schedule.Events = append(schedule.Events, record.Event)
+	}
+	return schedule
+}
+
+// Run executes the schedule against the target, waiting for the offset of each event.
+// It stops at the first failing event or when ctx is done, the timeline covers every event executed so far.
+func (s *Schedule) Run(ctx context.Context, target Target) (*Timeline, error) {
+	timeline := &Timeline{Seed: s.Seed}
+	var degraded []network.LinkShape
+	start := time.Now()
+
+	for _, event := range s.Events {
+		select {
+		case <-ctx.Done():
+			return timeline, ctx.Err()
+		case <-time.After(time.Until(start.Add(event.At))):
+		}
+
+		slog.Info("chaos event", "seed", s.Seed, "at", event.At, "action", event.Action, "node", event.Node, "groups", event.Groups)
+		record := Record{Event: event, Time: time.Now()}
+
+		var err error
+		switch event.Action {
+		case ActionKill:
+			err = target.StopNode(event.Node)
+		case ActionStart:
+			err = target.StartNode(event.Node)
+		case ActionRestart:
+			if err = target.StopNode(event.Node); err == nil {
+				err = target.StartNode(event.Node)
+			}
+		case ActionPause:
+			err = target.PauseNode(event.Node)
+		case ActionResume:
+			err = target.ResumeNode(event.Node)
+		case ActionPartition:
+			err = target.Partition(event.Groups...)
+		case ActionHeal:
+			err = target.Heal()
+		case ActionDegrade, ActionRestore:
+			// link shaping replaces the shapes of the whole network, so every degraded node is sent each time
+			degraded = slices.DeleteFunc(degraded, func(shape network.LinkShape) bool { return shape.Node == event.Node })
+			if event.Action == ActionDegrade {
+				if event.Profile == nil {
+					err = fmt.Errorf("degrade event without a link profile")
+					break
+				}
+				degraded = append(degraded, network.LinkShape{Node: event.Node, Profile: *event.Profile})
+			}
+			err = target.ShapeLinks(degraded...)
+		default:
+			err = fmt.Errorf("unknown action %q", event.Action)
+		}
+
+		if err != nil {
+			record.Error = err.Error()
+			timeline.Records = append(timeline.Records, record)
+			return timeline, fmt.Errorf("chaos %s at %s failed: %w", event.Action, event.At, err)
+		}
+		timeline.Records = append(timeline.Records, record)
+	}
+	return timeline, nil
+}
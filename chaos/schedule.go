@@ -0,0 +1,321 @@
+// Package chaos injects a seeded random schedule of faults into a running network.
+// A schedule is fully determined by its Config and the network's node IDs, so the exact
+// fault sequence of a failed run can be replayed from its seed or from the recorded Timeline.
+package chaos
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/network"
+)
+
+// Action is a step of a chaos schedule, either a fault or the recovery from one
+type Action string
+
+// Faults
+const (
+	// ActionKill stops a node until a later ActionStart
+	ActionKill Action = "kill"
+	// ActionRestart stops a node and starts it again right away
+	ActionRestart Action = "restart"
+	// ActionPause freezes a node until a later ActionResume
+	ActionPause Action = "pause"
+	// ActionPartition splits the network into groups until a later ActionHeal
+	ActionPartition Action = "partition"
+	// ActionDegrade shapes every link of a node until a later ActionRestore
+	ActionDegrade Action = "degrade"
+)
+
+// faultCapabilities are the environment capabilities faults need, kills and restarts work everywhere
+var faultCapabilities = map[Action]environments.Capability{
+	ActionPause:     environments.CapabilityPause,
+	ActionPartition: environments.CapabilityPartition,
+	ActionDegrade:   environments.CapabilityLinkShaping,
+}
+
+// Recoveries
+const (
+	ActionStart   Action = "start"
+	ActionResume  Action = "resume"
+	ActionHeal    Action = "heal"
+	ActionRestore Action = "restore"
+)
+
+// Config configures the faults of a schedule
+type Config struct {
+	Seed          int64
+	Duration      time.Duration        // how long faults are injected, every fault is recovered by then
+	Interval      time.Duration        // time between fault attempts, 10s by default
+	FaultDuration time.Duration        // longest time a fault lasts, twice the interval by default
+	Faults        []Action             // faults to inject, all those Supports allows by default
+	Validators    []string             // nodes counted against MaxDown, every node by default
+	MaxDown       *int                 // most validators killed, paused or partitioned away at once, a third when nil
+	LinkProfile   *network.LinkProfile // profile of degraded links, the LinkLossy preset by default
+	// Supports reports the capabilities of the target's environment, e.g. (*client.Client).Supports.
+	// Faults it cannot inject are left out, every fault is assumed supported when nil.
+	Supports func(environments.Capability) bool
+}
+
+// Event is a scheduled action, At is the offset from the start of the run
+type Event struct {
+	At      time.Duration        `json:"at"`
+	Action  Action               `json:"action"`
+	Node    string               `json:"node,omitempty"`
+	Groups  [][]string           `json:"groups,omitempty"`
+	Profile *network.LinkProfile `json:"profile,omitempty"`
+}
+
+// Schedule is the ordered list of events generated from a seed
+type Schedule struct {
+	Seed   int64   `json:"seed"`
+	Events []Event `json:"events"`
+}
+
+// NewSchedule generates the fault schedule of the network's nodes.
+// Each node has at most one fault at a time, partitions never overlap with killed or restarted nodes,
+// and no more than MaxDown validators are unavailable at any time.
+func NewSchedule(cfg Config, networkCfg *network.Network) (*Schedule, error) {
+	cfg, err := withDefaults(cfg, networkCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scheduler{
+		cfg:      cfg,
+		rng:      rand.New(rand.NewSource(cfg.Seed)),
+		nodes:    nodeIDs(networkCfg),
+		faults:   make(map[string]Action),
+		schedule: &Schedule{Seed: cfg.Seed},
+	}
+
+	for at := cfg.Interval; at < cfg.Duration; at += cfg.Interval {
+		s.recoverUntil(at)
+		s.inject(at)
+	}
+	s.recoverUntil(cfg.Duration)
+	return s.schedule, nil
+}
+
+// withDefaults validates the config and fills in its defaults
+func withDefaults(cfg Config, networkCfg *network.Network) (Config, error) {
+	ids := nodeIDs(networkCfg)
+	if len(ids) == 0 {
+		return cfg, fmt.Errorf("no nodes defined in the network")
+	}
+	if cfg.Duration <= 0 {
+		return cfg, fmt.Errorf("duration must be positive")
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.FaultDuration <= 0 {
+		cfg.FaultDuration = 2 * cfg.Interval
+	}
+	supported := func(fault Action) bool {
+		capability, ok := faultCapabilities[fault]
+		return !ok || cfg.Supports == nil || cfg.Supports(capability)
+	}
+	if len(cfg.Faults) == 0 {
+		for _, fault := range []Action{ActionKill, ActionRestart, ActionPause, ActionPartition, ActionDegrade} {
+			if supported(fault) {
+				cfg.Faults = append(cfg.Faults, fault)
+			}
+		}
+	}
+	for _, fault := range cfg.Faults {
+		if !slices.Contains([]Action{ActionKill, ActionRestart, ActionPause, ActionPartition, ActionDegrade}, fault) {
+			return cfg, fmt.Errorf("unknown fault %q", fault)
+		}
+		if !supported(fault) {
+			return cfg, fmt.Errorf("fault %q needs an environment with the %s capability", fault, faultCapabilities[fault])
+		}
+	}
+
+	if len(cfg.Validators) == 0 {
+		cfg.Validators = ids
+	}
+	for _, id := range cfg.Validators {
+		if !slices.Contains(ids, id) {
+			return cfg, fmt.Errorf("unknown validator %s", id)
+		}
+	}
+	if cfg.MaxDown == nil {
+		maxDown := len(cfg.Validators) / 3
+		cfg.MaxDown = &maxDown
+	} else if *cfg.MaxDown < 0 {
+		return cfg, fmt.Errorf("max down cannot be negative")
+	}
+
+	if cfg.LinkProfile == nil {
+		profile, err := network.LinkPreset(network.LinkLossy)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.LinkProfile = &profile
+	}
+	return cfg, nil
+}
+
+// recovery is a pending recovery event and the fault it ends
+type recovery struct {
+	event Event
+	fault Action
+}
+
+// scheduler tracks the simulated state of the network while the schedule is generated
+type scheduler struct {
+	cfg      Config
+	rng      *rand.Rand
+	nodes    []string
+	faults   map[string]Action // active fault of each node
+	minority []string          // validators partitioned away from the majority
+	pending  []recovery
+	schedule *Schedule
+}
+
+// inject adds a random fault at the given offset, if any node can take it
+func (s *scheduler) inject(at time.Duration) {
+	fault := s.cfg.Faults[s.rng.Intn(len(s.cfg.Faults))]
+
+	switch fault {
+	case ActionKill, ActionRestart, ActionPause:
+		var candidates []string
+		for _, id := range s.nodes {
+			if s.faults[id] != "" || slices.Contains(s.minority, id) {
+				continue
+			}
+			// restarted containers would lose the partition rules
+			if fault != ActionPause && len(s.minority) > 0 {
+				continue
+			}
+			if slices.Contains(s.cfg.Validators, id) && s.down()+1 > *s.cfg.MaxDown {
+				continue
+			}
+			candidates = append(candidates, id)
+		}
+		if len(candidates) == 0 {
+			return
+		}
+		id := candidates[s.rng.Intn(len(candidates))]
+		s.add(Event{At: at, Action: fault, Node: id})
+
+		switch fault {
+		case ActionKill:
+			s.faults[id] = fault
+			s.recoverAt(at, Event{Action: ActionStart, Node: id}, fault)
+		case ActionPause:
+			s.faults[id] = fault
+			s.recoverAt(at, Event{Action: ActionResume, Node: id}, fault)
+		}
+
+	case ActionPartition:
+		if len(s.minority) > 0 || s.anyKilled() || len(s.nodes) < 2 {
+			return
+		}
+		var candidates []string
+		for _, id := range s.cfg.Validators {
+			if s.faults[id] == "" {
+				candidates = append(candidates, id)
+			}
+		}
+		room := *s.cfg.MaxDown - s.down()
+		if room <= 0 || len(candidates) == 0 {
+			return
+		}
+		// the majority keeps at least one node
+		size := 1 + s.rng.Intn(min(room, len(candidates), len(s.nodes)-1))
+		s.rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+		minority := candidates[:size]
+
+		var majority []string
+		for _, id := range s.nodes {
+			if !slices.Contains(minority, id) {
+				majority = append(majority, id)
+			}
+		}
+		// keep the network order within each group
+		slices.SortFunc(minority, func(a, b string) int { return slices.Index(s.nodes, a) - slices.Index(s.nodes, b) })
+
+		s.minority = minority
+		s.add(Event{At: at, Action: ActionPartition, Groups: [][]string{majority, minority}})
+		s.recoverAt(at, Event{Action: ActionHeal}, fault)
+
+	case ActionDegrade:
+		var candidates []string
+		for _, id := range s.nodes {
+			if s.faults[id] == "" {
+				candidates = append(candidates, id)
+			}
+		}
+		if len(candidates) == 0 {
+			return
+		}
+		id := candidates[s.rng.Intn(len(candidates))]
+		s.faults[id] = fault
+		s.add(Event{At: at, Action: ActionDegrade, Node: id, Profile: s.cfg.LinkProfile})
+		s.recoverAt(at, Event{Action: ActionRestore, Node: id}, fault)
+	}
+}
+
+// recoverAt schedules the recovery of a fault injected at the given offset
+func (s *scheduler) recoverAt(at time.Duration, event Event, fault Action) {
+	half := s.cfg.FaultDuration / 2
+	event.At = min(at+half+time.Duration(s.rng.Int63n(int64(half)+1)), s.cfg.Duration)
+	s.pending = append(s.pending, recovery{event: event, fault: fault})
+	slices.SortStableFunc(s.pending, func(a, b recovery) int { return cmp.Compare(a.event.At, b.event.At) })
+}
+
+// recoverUntil adds every pending recovery due by the given offset
+func (s *scheduler) recoverUntil(at time.Duration) {
+	for len(s.pending) > 0 && s.pending[0].event.At <= at {
+		r := s.pending[0]
+		s.pending = s.pending[1:]
+		if r.fault == ActionPartition {
+			s.minority = nil
+		} else {
+			delete(s.faults, r.event.Node)
+		}
+		s.add(r.event)
+	}
+}
+
+// down counts the validators that are currently killed, paused or partitioned away
+func (s *scheduler) down() int {
+	count := 0
+	for _, id := range s.cfg.Validators {
+		fault := s.faults[id]
+		if fault == ActionKill || fault == ActionPause || slices.Contains(s.minority, id) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *scheduler) add(event Event) {
+	s.schedule.Events = append(s.schedule.Events, event)
+}
+
+// anyKilled reports whether a node is currently killed
+func (s *scheduler) anyKilled() bool {
+	for _, fault := range s.faults {
+		if fault == ActionKill {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeIDs returns the IDs of the network's nodes in order
+func nodeIDs(networkCfg *network.Network) []string {
+	ids := make([]string, 0, len(networkCfg.Nodes))
+	for _, nodeCfg := range networkCfg.Nodes {
+		ids = append(ids, nodeCfg.GetID())
+	}
+	return ids
+}
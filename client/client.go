@@ -1,13 +1,19 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/vechain/networkhub/genesisbuilder"
 	"github.com/vechain/networkhub/internal/environments"
 	"github.com/vechain/networkhub/internal/environments/launcher"
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
 )
 
 type Client struct {
@@ -103,3 +109,159 @@ func (c *Client) RemoveNode(nodeID string) error {
 
 	return nil
 }
+
+// StopNode stops a running node but keeps it in the network, StartNode brings it back
+func (c *Client) StopNode(nodeID string) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.StopNode(nodeID)
+}
+
+// StartNode starts a stopped node of the running network
+func (c *Client) StartNode(nodeID string) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.StartNode(nodeID)
+}
+
+// PauseNode freezes a running node in place, keeping its process and state
+func (c *Client) PauseNode(nodeID string) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.PauseNode(nodeID)
+}
+
+// ResumeNode resumes a paused node
+func (c *Client) ResumeNode(nodeID string) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.ResumeNode(nodeID)
+}
+
+// Exec runs argv on a running node and returns its output and exit code
+func (c *Client) Exec(nodeID string, argv []string) (*node.ExecResult, error) {
+	if c.actions == nil {
+		return nil, fmt.Errorf("no network loaded")
+	}
+	return c.actions.Exec(nodeID, argv)
+}
+
+// CopyFrom returns a tar archive of the file or directory at path on a running node.
+// Relative paths are resolved against the node's data directory.
+func (c *Client) CopyFrom(nodeID, path string) (io.ReadCloser, error) {
+	if c.actions == nil {
+		return nil, fmt.Errorf("no network loaded")
+	}
+	return c.actions.CopyFrom(nodeID, path)
+}
+
+// CopyTo extracts a tar archive into the directory at path on a running node.
+// Relative paths are resolved against the node's data directory.
+func (c *Client) CopyTo(nodeID, path string, archive io.Reader) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.CopyTo(nodeID, path, archive)
+}
+
+// Partition splits the running network into groups of node IDs that cannot reach each other.
+// Every node must be in exactly one group. While partitioned, HealthCheck expects nodes to peer
+// and agree on blocks only within their group.
+func (c *Client) Partition(groups ...[]string) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.Partition(groups...)
+}
+
+// Heal restores traffic between all nodes after a partition
+func (c *Client) Heal() error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.Heal()
+}
+
+// ShapeLinks replaces the delay, jitter, loss and bandwidth of the links between running nodes,
+// e.g. to place a validator on another continent. Shapes apply in order, later ones override
+// earlier ones. Links not covered by shapes are left unshaped.
+func (c *Client) ShapeLinks(shapes ...network.LinkShape) error {
+	if c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.actions.ShapeLinks(shapes...)
+}
+
+// ResetLinks removes the shaping of every link
+func (c *Client) ResetLinks() error {
+	return c.ShapeLinks()
+}
+
+// WaitForBlock waits until every node's best block is at least the given number
+func (c *Client) WaitForBlock(ctx context.Context, number uint32) error {
+	if c.network == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.network.WaitForBlock(ctx, number)
+}
+
+// WaitForFinalized waits until every node has finalized the given block number
+func (c *Client) WaitForFinalized(ctx context.Context, number uint32) error {
+	if c.network == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.network.WaitForFinalized(ctx, number)
+}
+
+// WaitForTxReceipt waits until every node has included the transaction and returns its receipt
+func (c *Client) WaitForTxReceipt(ctx context.Context, id thor.Bytes32) (*api.Receipt, error) {
+	if c.network == nil {
+		return nil, fmt.Errorf("no network loaded")
+	}
+	return c.network.WaitForTxReceipt(ctx, id)
+}
+
+// WaitForAllNodes waits until every node satisfies the condition
+func (c *Client) WaitForAllNodes(ctx context.Context, condition network.NodeCondition) error {
+	if c.network == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	return c.network.WaitForAllNodes(ctx, condition)
+}
+
+// MetricsEndpoints returns the URL of the Prometheus metrics endpoint of every node with metrics enabled
+func (c *Client) MetricsEndpoints() (map[string]string, error) {
+	if c.network == nil {
+		return nil, fmt.Errorf("no network loaded")
+	}
+	return c.network.MetricsEndpoints(), nil
+}
+
+// WriteScrapeConfig writes a Prometheus configuration scraping the running nodes and networkhub's own
+// metrics served on hubAddr, see metrics.Serve. An empty hubAddr only scrapes the nodes.
+func (c *Client) WriteScrapeConfig(path, hubAddr string) error {
+	if c.network == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	if err := os.WriteFile(path, []byte(c.network.ScrapeConfig(hubAddr)), 0644); err != nil {
+		return fmt.Errorf("failed to write scrape config: %w", err)
+	}
+	return nil
+}
+
+// ExportSnapshot writes the accounts at the block revision as a CSV or JSON snapshot genesisbuilder.LoadSnapshot
+// reads back, see network.ExportAccounts. Addresses default to the accounts of the genesis.
+func (c *Client) ExportSnapshot(path, revision string, addresses ...thor.Address) error {
+	if c.network == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	accounts, err := c.network.ExportAccounts(revision, addresses...)
+	if err != nil {
+		return err
+	}
+	return genesisbuilder.WriteSnapshot(path, accounts)
+}
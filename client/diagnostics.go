@@ -0,0 +1,244 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/vechain/networkhub/network/node"
+	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/thorclient"
+)
+
+// redacted replaces node keys in the bundled network config
+const redacted = "REDACTED"
+
+// DiagnosticsOption configures the content of a diagnostics bundle
+type DiagnosticsOption func(*diagnosticsOptions)
+
+type diagnosticsOptions struct {
+	dataDirs bool
+}
+
+// WithDataDirs adds the data directory of every running node to the bundle, as nodes/<id>/datadir.tar
+func WithDataDirs() DiagnosticsOption {
+	return func(o *diagnosticsOptions) {
+		o.dataDirs = true
+	}
+}
+
+// CollectDiagnostics writes a gzipped tarball to path for attaching to bug reports. It holds the network
+// config with node keys redacted, the last health report, and per node its genesis, thor command line,
+// version, logs and peers. Collection is best effort: what could not be collected is listed in errors.txt.
+func (c *Client) CollectDiagnostics(path string, opts ...DiagnosticsOption) (err error) {
+	if c.network == nil || c.actions == nil {
+		return fmt.Errorf("no network loaded")
+	}
+	options := &diagnosticsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create diagnostics bundle: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write diagnostics bundle: %w", closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	b := &bundle{tw: tar.NewWriter(gz), modTime: time.Now()}
+	if err := c.collect(b, options); err != nil {
+		return err
+	}
+	if len(b.problems) > 0 {
+		if err := b.add("errors.txt", []byte(strings.Join(b.problems, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err := b.tw.Close(); err != nil {
+		return fmt.Errorf("failed to write diagnostics bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write diagnostics bundle: %w", err)
+	}
+	return nil
+}
+
+// collect adds every file of the bundle, only failures to write the bundle itself are returned
+func (c *Client) collect(b *bundle, options *diagnosticsOptions) error {
+	config, err := c.redactedConfig()
+	if err != nil {
+		b.problem("network config: %v", err)
+	} else if err := b.add("network.json", config); err != nil {
+		return err
+	}
+
+	// Without a previous check, report the current state
+	report := c.network.LastHealth()
+	if report == nil {
+		if report, err = c.network.HealthReport(0, 0); err != nil {
+			b.problem("health report: %v", err)
+		}
+	}
+	if report != nil {
+		if err := b.addJSON("health.json", report); err != nil {
+			return err
+		}
+	}
+
+	running := c.actions.Nodes()
+	for _, nodeCfg := range c.network.Nodes {
+		if err := c.collectNode(b, nodeCfg, running[nodeCfg.GetID()], options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectNode adds the files of a node under nodes/<id>/
+func (c *Client) collectNode(b *bundle, nodeCfg node.Config, instance node.Lifecycle, options *diagnosticsOptions) error {
+	id := nodeCfg.GetID()
+	dir := path.Join("nodes", id)
+
+	if gen := nodeCfg.GetGenesis(); gen != nil {
+		genesisBytes, err := nodegenesis.MarshalFor(gen, nodeCfg.GetThorVersion())
+		if err != nil {
+			b.problem("node %s genesis: %v", id, err)
+		} else if err := b.add(path.Join(dir, "genesis.json"), genesisBytes); err != nil {
+			return err
+		}
+	}
+
+	if instance == nil {
+		b.problem("node %s is not running", id)
+		return nil
+	}
+
+	if inspector, ok := instance.(node.Inspector); ok {
+		if err := b.add(path.Join(dir, "command.txt"), []byte(strings.Join(inspector.CommandLine(), " ")+"\n")); err != nil {
+			return err
+		}
+		if logs, err := inspector.Logs(); err != nil {
+			b.problem("node %s logs: %v", id, err)
+		} else if err := b.addReader(path.Join(dir, "thor.log"), logs); err != nil {
+			return err
+		}
+	} else {
+		b.problem("node %s does not report its command line and logs", id)
+	}
+
+	if result, err := c.actions.Exec(id, []string{"thor", "--version"}); err != nil {
+		b.problem("node %s version: %v", id, err)
+	} else if result.ExitCode != 0 {
+		b.problem("node %s version: thor --version exited with %d: %s", id, result.ExitCode, result.Stderr)
+	} else if err := b.add(path.Join(dir, "version.txt"), []byte(result.Stdout)); err != nil {
+		return err
+	}
+
+	if peers, err := thorclient.New(nodeCfg.GetHTTPAddr()).Peers(); err != nil {
+		b.problem("node %s peers: %v", id, err)
+	} else if err := b.addJSON(path.Join(dir, "peers.json"), peers); err != nil {
+		return err
+	}
+
+	if options.dataDirs {
+		if archive, err := c.actions.CopyFrom(id, nodeCfg.GetDataDir()); err != nil {
+			b.problem("node %s data dir: %v", id, err)
+		} else if err := b.addReader(path.Join(dir, "datadir.tar"), archive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redactedConfig returns the network config as JSON with the node keys replaced
+func (c *Client) redactedConfig() ([]byte, error) {
+	data, err := json.Marshal(c.network)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	nodes, _ := config["nodes"].([]any)
+	for _, n := range nodes {
+		if nodeCfg, ok := n.(map[string]any); ok && nodeCfg["key"] != "" {
+			nodeCfg["key"] = redacted
+		}
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// bundle writes the files of a diagnostics tarball and records what could not be collected
+type bundle struct {
+	tw       *tar.Writer
+	modTime  time.Time
+	problems []string
+}
+
+func (b *bundle) problem(format string, args ...any) {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+}
+
+func (b *bundle) add(name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: b.modTime}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to diagnostics bundle: %w", name, err)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to diagnostics bundle: %w", name, err)
+	}
+	return nil
+}
+
+func (b *bundle) addJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.problem("%s: %v", name, err)
+		return nil
+	}
+	return b.add(name, data)
+}
+
+// addReader adds the content of r, which is closed. Tar headers need the size upfront, so the content
+// is spooled to a temporary file rather than held in memory, data dirs can be large.
+func (b *bundle) addReader(name string, r io.ReadCloser) error {
+	defer r.Close()
+
+	spool, err := os.CreateTemp("", "networkhub-diagnostics-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	size, err := io.Copy(spool, r)
+	if err != nil {
+		b.problem("%s: %v", name, err)
+		return nil
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read temporary file: %w", err)
+	}
+
+	header := &tar.Header{Name: name, Mode: 0644, Size: size, ModTime: b.modTime}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to diagnostics bundle: %w", name, err)
+	}
+	if _, err := io.Copy(b.tw, spool); err != nil {
+		return fmt.Errorf("failed to write %s to diagnostics bundle: %w", name, err)
+	}
+	return nil
+}
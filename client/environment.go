@@ -0,0 +1,62 @@
+package client
+
+import (
+	"github.com/vechain/networkhub/internal/environments"
+)
+
+// EnvironmentManager runs the nodes of a custom environment, see RegisterEnvironment
+type EnvironmentManager = environments.Manager
+
+// EnvironmentFactory creates the manager for a network using a custom environment
+type EnvironmentFactory = environments.Factory
+
+// EnvironmentCapability names an optional feature an environment may support
+type EnvironmentCapability = environments.Capability
+
+// Optional environment features, reported by EnvironmentManager.Capabilities
+const (
+	CapabilityExec           = environments.CapabilityExec
+	CapabilityPublicNetworks = environments.CapabilityPublicNetworks
+	CapabilityThorBuild      = environments.CapabilityThorBuild
+	CapabilityMixedNetworks  = environments.CapabilityMixedNetworks
+	CapabilityPartition      = environments.CapabilityPartition
+	CapabilityLinkShaping    = environments.CapabilityLinkShaping
+	CapabilityPause          = environments.CapabilityPause
+	CapabilityClockSkew      = environments.CapabilityClockSkew
+	CapabilityMetrics        = environments.CapabilityMetrics
+)
+
+// EnvironmentHostBridge lets the nodes of a custom environment peer with other environments
+// through the host; managers listing CapabilityMixedNetworks must implement it
+type EnvironmentHostBridge = environments.HostBridge
+
+// EnvironmentPartitioner lets a custom environment split its nodes into groups that cannot reach
+// each other; managers listing CapabilityPartition must implement it
+type EnvironmentPartitioner = environments.Partitioner
+
+// EnvironmentLinkShaper lets a custom environment shape the traffic between its nodes;
+// managers listing CapabilityLinkShaping must implement it
+type EnvironmentLinkShaper = environments.LinkShaper
+
+// EnvironmentRestarter lets a custom environment kill a node and restart it with its chain data,
+// as StopNode and StartNode otherwise start the node afresh
+type EnvironmentRestarter = environments.Restarter
+
+// RegisterEnvironment makes a custom environment (e.g. podman or remote hosts) available
+// to networks whose Environment is name. Built-in environment names cannot be reused.
+func RegisterEnvironment(name string, factory EnvironmentFactory) error {
+	return environments.Register(name, factory)
+}
+
+// RegisteredEnvironments returns the names of all available environments
+func RegisteredEnvironments() []string {
+	return environments.Registered()
+}
+
+// Supports reports whether the network's environment supports an optional capability
+func (c *Client) Supports(capability EnvironmentCapability) bool {
+	if c.actions == nil {
+		return false
+	}
+	return c.actions.Supports(capability)
+}
@@ -0,0 +1,410 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+	"github.com/vechain/networkhub/thorbuilder"
+)
+
+// registerEnvironment registers a custom environment for the duration of the test
+func registerEnvironment(t *testing.T, name string, factory EnvironmentFactory) {
+	t.Helper()
+	require.NoError(t, RegisterEnvironment(name, factory))
+	t.Cleanup(func() {
+		environments.Unregister(name)
+	})
+}
+
+// recordingManager is a custom environment that records the calls made by the launcher
+type recordingManager struct {
+	calls        []string
+	capabilities []EnvironmentCapability
+}
+
+type recordingNode struct{}
+
+func (n *recordingNode) Start() error { return nil }
+func (n *recordingNode) Stop() error  { return nil }
+
+func (m *recordingManager) ValidateNode(nodeCfg node.Config) error {
+	m.calls = append(m.calls, "validate "+nodeCfg.GetID())
+	return nil
+}
+
+func (m *recordingManager) BuildThorBinary(_ *thorbuilder.Config) (string, error) {
+	m.calls = append(m.calls, "build")
+	return "/opt/thor", nil
+}
+
+func (m *recordingManager) GenerateEnodes(_ *network.Network) ([]string, error) {
+	m.calls = append(m.calls, "enodes")
+	return nil, nil
+}
+
+func (m *recordingManager) StartNode(nodeCfg node.Config, _ *network.Network, _ []string) (node.Lifecycle, error) {
+	m.calls = append(m.calls, "start "+nodeCfg.GetID())
+	return &recordingNode{}, nil
+}
+
+func (m *recordingManager) StopNode(_ node.Lifecycle) error {
+	m.calls = append(m.calls, "stop")
+	return nil
+}
+
+func (m *recordingManager) Cleanup() error {
+	m.calls = append(m.calls, "cleanup")
+	return nil
+}
+
+func (m *recordingManager) Capabilities() []EnvironmentCapability {
+	return m.capabilities
+}
+
+func TestRegisterEnvironment(t *testing.T) {
+	manager := &recordingManager{capabilities: []EnvironmentCapability{CapabilityThorBuild}}
+	registerEnvironment(t, "recording", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+	assert.Contains(t, RegisteredEnvironments(), "recording")
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "recording"
+	networkCfg.Nodes = networkCfg.Nodes[:1]
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+
+	assert.True(t, c.Supports(CapabilityThorBuild))
+	assert.False(t, c.Supports(CapabilityExec))
+	_, err = c.Exec("node1", []string{"true"})
+	assert.ErrorContains(t, err, "recording environment does not support exec")
+	assert.ErrorContains(t, c.Partition([]string{"node1"}), "recording environment does not support partitions")
+	assert.ErrorContains(t, c.ShapeLinks(), "recording environment does not support link shaping")
+
+	require.NoError(t, c.Stop())
+	assert.Equal(t, []string{"build", "enodes", "validate node1", "start node1", "stop", "cleanup"}, manager.calls)
+	assert.Equal(t, "/opt/thor", networkCfg.Nodes[0].GetExecArtifact())
+}
+
+func TestRegisterEnvironmentWithoutThorBuild(t *testing.T) {
+	manager := &recordingManager{}
+	registerEnvironment(t, "recording-prebuilt", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "recording-prebuilt"
+	networkCfg.Nodes = networkCfg.Nodes[:1]
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	require.NoError(t, c.Stop())
+
+	// Environments without CapabilityThorBuild are never asked to build thor
+	assert.Equal(t, []string{"enodes", "validate node1", "start node1", "stop", "cleanup"}, manager.calls)
+	assert.Empty(t, networkCfg.Nodes[0].GetExecArtifact())
+}
+
+func TestRegisterEnvironmentErrors(t *testing.T) {
+	factory := func(_ *network.Network) (EnvironmentManager, error) {
+		return &recordingManager{}, nil
+	}
+
+	assert.ErrorContains(t, RegisterEnvironment(environments.Local, factory), "already registered")
+	assert.Error(t, RegisterEnvironment("", factory))
+	assert.Error(t, RegisterEnvironment("nil-factory", nil))
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "unknown"
+	_, err := New(networkCfg)
+	assert.ErrorContains(t, err, "unsupported environment: unknown")
+}
+
+// bridgedManager is a custom environment whose nodes peer with other environments through the host
+type bridgedManager struct {
+	recordingManager
+	nodeIP string
+	hostIP string
+	enodes map[string][]string
+}
+
+func (m *bridgedManager) GenerateEnodes(networkCfg *network.Network) ([]string, error) {
+	var enodes []string
+	for _, nodeCfg := range networkCfg.Nodes {
+		enode, err := nodeCfg.Enode(m.nodeIP)
+		if err != nil {
+			return nil, err
+		}
+		enodes = append(enodes, enode)
+	}
+	return enodes, nil
+}
+
+func (m *bridgedManager) StartNode(nodeCfg node.Config, _ *network.Network, enodes []string) (node.Lifecycle, error) {
+	m.enodes[nodeCfg.GetID()] = enodes
+	return &recordingNode{}, nil
+}
+
+func (m *bridgedManager) Capabilities() []EnvironmentCapability {
+	return []EnvironmentCapability{CapabilityMixedNetworks}
+}
+
+func (m *bridgedManager) HostEnode(nodeCfg node.Config) (string, error) {
+	return nodeCfg.Enode("127.0.0.1")
+}
+
+func (m *bridgedManager) HostIP() string {
+	return m.hostIP
+}
+
+func TestMixedEnvironmentNetwork(t *testing.T) {
+	// "host" runs nodes on the host, "bridge" runs them behind a gateway like Docker does
+	host := &bridgedManager{nodeIP: "127.0.0.1", hostIP: "127.0.0.1", enodes: map[string][]string{}}
+	bridge := &bridgedManager{nodeIP: "10.0.0.2", hostIP: "10.0.0.1", enodes: map[string][]string{}}
+	registerEnvironment(t, "mixed-host", func(_ *network.Network) (EnvironmentManager, error) {
+		return host, nil
+	})
+	registerEnvironment(t, "mixed-bridge", func(_ *network.Network) (EnvironmentManager, error) {
+		return bridge, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "mixed-host"
+	networkCfg.Nodes[2].SetEnvironment("mixed-bridge")
+	node1, node2, node3 := networkCfg.Nodes[0], networkCfg.Nodes[1], networkCfg.Nodes[2]
+
+	enode := func(nodeCfg node.Config, ip string) string {
+		enode, err := nodeCfg.Enode(ip)
+		require.NoError(t, err)
+		return enode
+	}
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.Stop())
+	})
+
+	// Host nodes dial the bridged node through its host published port
+	hostView := []string{enode(node1, "127.0.0.1"), enode(node2, "127.0.0.1"), enode(node3, "127.0.0.1")}
+	assert.Equal(t, hostView, host.enodes["node1"])
+	assert.Equal(t, hostView, host.enodes["node2"])
+
+	// The bridged node dials host nodes through its gateway
+	assert.Equal(t, []string{enode(node3, "10.0.0.2"), enode(node1, "10.0.0.1"), enode(node2, "10.0.0.1")}, bridge.enodes["node3"])
+	assert.NotContains(t, host.enodes, "node3")
+}
+
+func TestMixedEnvironmentRequiresHostBridge(t *testing.T) {
+	registerEnvironment(t, "unbridged", func(_ *network.Network) (EnvironmentManager, error) {
+		return &recordingManager{}, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.Fake
+	networkCfg.Nodes[2].SetEnvironment("unbridged")
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.Start(), "cannot peer with nodes of other environments")
+}
+
+func TestTopologyNarrowsBootnodes(t *testing.T) {
+	manager := &bridgedManager{nodeIP: "127.0.0.1", hostIP: "127.0.0.1", enodes: map[string][]string{}}
+	registerEnvironment(t, "topology", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "topology"
+	networkCfg.Topology = &network.Topology{Shape: network.TopologyLine}
+	enodes := make([]string, len(networkCfg.Nodes))
+	for i, nodeCfg := range networkCfg.Nodes {
+		enode, err := nodeCfg.Enode("127.0.0.1")
+		require.NoError(t, err)
+		enodes[i] = enode
+	}
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.Stop())
+	})
+
+	assert.Equal(t, []string{enodes[1]}, manager.enodes["node1"])
+	assert.Equal(t, []string{enodes[0], enodes[2]}, manager.enodes["node2"])
+	assert.Equal(t, []string{enodes[1]}, manager.enodes["node3"])
+}
+
+// shapingManager is a custom environment that records the links it is asked to shape
+type shapingManager struct {
+	recordingManager
+	links []network.Links
+}
+
+func (m *shapingManager) Capabilities() []EnvironmentCapability {
+	return []EnvironmentCapability{CapabilityLinkShaping}
+}
+
+func (m *shapingManager) ShapeLinks(links network.Links) error {
+	m.links = append(m.links, links)
+	return nil
+}
+
+func TestShapeLinks(t *testing.T) {
+	manager := &shapingManager{}
+	registerEnvironment(t, "shaping", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "shaping"
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.ShapeLinks(), "network is not running")
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.Stop())
+	})
+
+	intercontinental, err := network.LinkPreset(network.LinkIntercontinental)
+	require.NoError(t, err)
+	require.NoError(t, c.ShapeLinks(network.LinkShape{Node: "node1", Peer: "node3", Profile: intercontinental}))
+	require.NoError(t, c.ResetLinks())
+	assert.ErrorContains(t, c.ShapeLinks(network.LinkShape{Node: "node4"}), "unknown node node4")
+
+	assert.Equal(t, []network.Links{
+		{"node1": {"node3": intercontinental}, "node3": {"node1": intercontinental}},
+		{},
+	}, manager.links)
+}
+
+// partitioningManager is a custom environment whose partitions fail partway
+type partitioningManager struct {
+	recordingManager
+}
+
+func (m *partitioningManager) Capabilities() []EnvironmentCapability {
+	return []EnvironmentCapability{CapabilityPartition}
+}
+
+func (m *partitioningManager) Partition(_ ...[]string) error {
+	m.calls = append(m.calls, "partition")
+	return errors.New("node3 is unreachable")
+}
+
+func (m *partitioningManager) Heal() error {
+	m.calls = append(m.calls, "heal")
+	return nil
+}
+
+func TestPartitionRollsBack(t *testing.T) {
+	manager := &partitioningManager{}
+	registerEnvironment(t, "partitioning", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "partitioning"
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.Stop())
+	})
+
+	err = c.Partition([]string{"node1", "node2"}, []string{"node3"})
+	assert.ErrorContains(t, err, "rolled back to a healed network")
+	assert.Equal(t, []string{"partition", "heal"}, manager.calls[len(manager.calls)-2:])
+	assert.False(t, networkCfg.IsPartitioned())
+	assert.Nil(t, networkCfg.Partition())
+}
+
+// restartingManager is a custom environment that restarts killed nodes with their data
+type restartingManager struct {
+	recordingManager
+}
+
+func (m *restartingManager) KillNode(_ node.Lifecycle) error {
+	m.calls = append(m.calls, "kill")
+	return nil
+}
+
+func (m *restartingManager) RestartNode(_ node.Lifecycle) error {
+	m.calls = append(m.calls, "restart")
+	return nil
+}
+
+func TestStopNodeKeepsRestartableNodes(t *testing.T) {
+	manager := &restartingManager{}
+	registerEnvironment(t, "restarting", func(_ *network.Network) (EnvironmentManager, error) {
+		return manager, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "restarting"
+	networkCfg.Nodes = networkCfg.Nodes[:1]
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+
+	// The killed node restarts as the same instance instead of a new one
+	require.NoError(t, c.StopNode("node1"))
+	assert.ErrorContains(t, c.StopNode("node1"), "is not running")
+	require.NoError(t, c.StartNode("node1"))
+	require.NoError(t, c.StopNode("node1"))
+
+	// Stopping the network releases killed nodes too
+	require.NoError(t, c.Stop())
+	assert.Equal(t, []string{"enodes", "validate node1", "start node1", "kill", "restart", "kill", "stop", "cleanup"}, manager.calls)
+}
+
+func TestClockSkewNeedsCapability(t *testing.T) {
+	registerEnvironment(t, "unskewed", func(_ *network.Network) (EnvironmentManager, error) {
+		return &recordingManager{}, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "unskewed"
+	networkCfg.Nodes[2].SetClockSkew(&node.ClockSkew{Offset: 3 * time.Second})
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.Start(), "unskewed environment cannot skew the clock of node node3")
+}
+
+func TestClockSkewNeedsSkewableThor(t *testing.T) {
+	registerEnvironment(t, "skewing", func(_ *network.Network) (EnvironmentManager, error) {
+		return &recordingManager{capabilities: []EnvironmentCapability{CapabilityThorBuild, CapabilityClockSkew}}, nil
+	})
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = "skewing"
+	networkCfg.ThorBuilder = thorbuilder.DefaultConfig()
+	networkCfg.Nodes[2].SetClockSkew(&node.ClockSkew{Offset: 3 * time.Second})
+
+	c, err := New(networkCfg)
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.Start(), "node node3 has a clock skew, which needs thor built with BuildConfig.SkewableClock")
+
+	networkCfg.ThorBuilder.BuildConfig.SkewableClock = true
+	c, err = New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	require.NoError(t, c.Stop())
+}
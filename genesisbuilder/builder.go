@@ -1,6 +1,7 @@
 package genesisbuilder
 
 import (
+	"slices"
 	"time"
 
 	"github.com/vechain/networkhub/network/node/genesis"
@@ -13,7 +14,12 @@ type Overrider func(genesis *genesis.CustomGenesis)
 type Builder struct {
 	maxBlockProposers     int
 	accounts              []thorgenesis.Account
+	snapshot              []thorgenesis.Account
 	stakers               []thorgenesis.Validator
+	authority             []thorgenesis.Authority
+	approvers             []thorgenesis.Approver
+	validators            []ValidatorKeys
+	contracts             []Contract
 	params                *thorgenesis.Params
 	forkConfig            *genesis.CustomGenesisForkConfig
 	overrider             Overrider
@@ -42,6 +48,19 @@ func (b *Builder) Stakers(stakers []thorgenesis.Validator) *Builder {
 	return b
 }
 
+// Authority sets the proof-of-authority nodes, which propose blocks until the network transitions to
+// proof of stake. A genesis with authority nodes gets no default stakers.
+func (b *Builder) Authority(authority []thorgenesis.Authority) *Builder {
+	b.authority = authority
+	return b
+}
+
+// Approvers sets the approvers of the built-in executor contract, which vote on governance proposals
+func (b *Builder) Approvers(approvers []thorgenesis.Approver) *Builder {
+	b.approvers = approvers
+	return b
+}
+
 func (b *Builder) Params(params thorgenesis.Params) *Builder {
 	b.params = &params
 	return b
@@ -76,12 +95,21 @@ func (b *Builder) Build() *genesis.CustomGenesis {
 	if len(b.accounts) == 0 {
 		b.accounts = DefaultAccounts()
 	}
-	if len(b.stakers) == 0 {
+	validators := b.validators
+	if len(b.stakers) == 0 && len(b.authority) == 0 {
 		b.stakers = DefaultStakers(b.maxBlockProposers)
+		// The dev accounts are funded by DefaultAccounts, the stakers derived beyond them are not
+		if devAccounts := len(thorgenesis.DevAccounts()); b.maxBlockProposers > devAccounts {
+			validators = slices.Concat(validators, DeriveValidators(DefaultSeed, b.maxBlockProposers)[devAccounts:])
+		}
 	}
 	if b.params == nil {
 		b.params = DefaultParams(uint64(b.maxBlockProposers))
 	}
+	accounts := append(MergeAccounts(b.accounts, b.snapshot), b.validatorAccounts(validators)...)
+	for _, contract := range b.contracts {
+		accounts = append(accounts, contract.Account())
+	}
 	if b.forkConfig == nil {
 		b.forkConfig = &genesis.CustomGenesisForkConfig{
 			ForkConfig: thor.SoloFork,
@@ -103,10 +131,11 @@ func (b *Builder) Build() *genesis.CustomGenesis {
 			LaunchTime: uint64(time.Now().Add(b.genesisTimestampDelay).Unix()),
 			GasLimit:   b.gasLimit,
 			ExtraData:  extraData,
-			Accounts:   b.accounts,
+			Accounts:   accounts,
+			Authority:  b.authority,
 			Stakers:    b.stakers,
 			Params:     *b.params,
-			Executor:   thorgenesis.Executor{},
+			Executor:   thorgenesis.Executor{Approvers: b.approvers},
 		},
 		ForkConfig: b.forkConfig,
 		Config:     b.config,
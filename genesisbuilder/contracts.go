@@ -0,0 +1,139 @@
+package genesisbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Artifact is a compiled contract, read from the JSON output of solc, Hardhat or Foundry
+type Artifact struct {
+	ContractName     string
+	ABI              json.RawMessage
+	Bytecode         []byte // creation code, which runs the constructor
+	DeployedBytecode []byte // runtime code, which the genesis places at the contract address
+}
+
+// LoadArtifact reads a compiled contract from a JSON file, see ParseArtifact
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read artifact: %w", err)
+	}
+	artifact, err := ParseArtifact(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact %s: %w", path, err)
+	}
+	return artifact, nil
+}
+
+// ParseArtifact parses a compiled contract. It accepts Hardhat artifacts, with bytecode and deployedBytecode
+// as hex strings, Foundry artifacts, with them as {"object": hex}, and solc standard JSON contract output,
+// with them under evm.
+func ParseArtifact(data []byte) (*Artifact, error) {
+	var raw struct {
+		ContractName     string          `json:"contractName"`
+		ABI              json.RawMessage `json:"abi"`
+		Bytecode         json.RawMessage `json:"bytecode"`
+		DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+		EVM              struct {
+			Bytecode         json.RawMessage `json:"bytecode"`
+			DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+		} `json:"evm"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Bytecode == nil && raw.DeployedBytecode == nil {
+		raw.Bytecode, raw.DeployedBytecode = raw.EVM.Bytecode, raw.EVM.DeployedBytecode
+	}
+
+	bytecode, err := parseBytecode(raw.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("bytecode: %w", err)
+	}
+	deployedBytecode, err := parseBytecode(raw.DeployedBytecode)
+	if err != nil {
+		return nil, fmt.Errorf("deployedBytecode: %w", err)
+	}
+	if len(deployedBytecode) == 0 {
+		return nil, fmt.Errorf("no deployedBytecode, abstract contracts and interfaces cannot be deployed")
+	}
+
+	return &Artifact{
+		ContractName:     raw.ContractName,
+		ABI:              raw.ABI,
+		Bytecode:         bytecode,
+		DeployedBytecode: deployedBytecode,
+	}, nil
+}
+
+// parseBytecode decodes a hex string or an object holding it, with or without the 0x prefix
+func parseBytecode(raw json.RawMessage) ([]byte, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var code string
+	if err := json.Unmarshal(raw, &code); err != nil {
+		var object struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, fmt.Errorf("expected a hex string or an object with one")
+		}
+		code = object.Object
+	}
+
+	// Placeholders of libraries look like __$<hash>$__, they must be linked at compile time
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("unlinked library references, link the libraries before deploying at genesis")
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	if code == "0x" {
+		return nil, nil
+	}
+	return hexutil.Decode(code)
+}
+
+// Contract is a contract deployed at genesis. The constructor does not run, so Storage must hold the
+// state it would have set.
+type Contract struct {
+	Address  thor.Address
+	Artifact *Artifact
+	Storage  Storage
+	Balance  *big.Int // VET held by the contract, none if nil
+	Energy   *big.Int // VTHO held by the contract, none if nil
+}
+
+// Contracts deploys the contracts at genesis, on top of the accounts set with Accounts or the default ones.
+// Contracts come after the accounts, so a contract at the address of an account overrides what it sets.
+func (b *Builder) Contracts(contracts ...Contract) *Builder {
+	b.contracts = append(b.contracts, contracts...)
+	return b
+}
+
+// Account returns the genesis account of the contract
+func (c Contract) Account() thorgenesis.Account {
+	account := thorgenesis.Account{
+		Address: c.Address,
+		Balance: (*thorgenesis.HexOrDecimal256)(c.Balance),
+		Energy:  (*thorgenesis.HexOrDecimal256)(c.Energy),
+		Code:    hexutil.Encode(c.Artifact.DeployedBytecode),
+	}
+	if len(c.Storage) > 0 {
+		account.Storage = make(map[string]thor.Bytes32, len(c.Storage))
+		for slot, value := range c.Storage {
+			account.Storage[slot.String()] = value
+		}
+	}
+	return account
+}
@@ -0,0 +1,93 @@
+package genesisbuilder
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestParseArtifact(t *testing.T) {
+	formats := map[string]string{
+		"Hardhat": `{"contractName":"Token","abi":[],"bytecode":"0x6080","deployedBytecode":"0x6001"}`,
+		"Foundry": `{"abi":[],"bytecode":{"object":"0x6080"},"deployedBytecode":{"object":"0x6001"}}`,
+		"solc":    `{"abi":[],"evm":{"bytecode":{"object":"6080"},"deployedBytecode":{"object":"6001"}}}`,
+	}
+	for name, data := range formats {
+		t.Run(name, func(t *testing.T) {
+			artifact, err := ParseArtifact([]byte(data))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x60, 0x80}, artifact.Bytecode)
+			assert.Equal(t, []byte{0x60, 0x01}, artifact.DeployedBytecode)
+			assert.JSONEq(t, `[]`, string(artifact.ABI))
+		})
+	}
+
+	_, err := ParseArtifact([]byte(`{"bytecode":"0x6080","deployedBytecode":"0x"}`))
+	assert.ErrorContains(t, err, "no deployedBytecode")
+
+	_, err = ParseArtifact([]byte(`{"deployedBytecode":"0x73__$1234$__6001"}`))
+	assert.ErrorContains(t, err, "unlinked library references")
+
+	path := filepath.Join(t.TempDir(), "Token.json")
+	require.NoError(t, os.WriteFile(path, []byte(formats["Hardhat"]), 0o644))
+	artifact, err := LoadArtifact(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Token", artifact.ContractName)
+}
+
+func TestStorage(t *testing.T) {
+	// Short strings hold their length times two in the last byte
+	storage := Storage{}.SetString(3, "Token")
+	assert.Equal(t, thor.MustParseBytes32("0x546f6b656e00000000000000000000000000000000000000000000000000000a"), storage[Slot(3)])
+
+	// Long strings hold their length times two plus one, and their data from keccak256(slot)
+	long := strings.Repeat("a", 40)
+	storage = Storage{}.SetString(0, long)
+	require.Len(t, storage, 3)
+	assert.Equal(t, Slot(81), storage[Slot(0)])
+	data := thor.Keccak256(Slot(0).Bytes())
+	assert.Equal(t, thor.BytesToBytes32([]byte(strings.Repeat("a", 32))), storage[data])
+
+	holder := thor.MustParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	owner := thor.MustParseAddress("0x61ff580b63d3845934610222245c116e013717ec")
+	storage = OpenZeppelinERC20.Storage(ERC20Token{
+		Name:     "Token",
+		Symbol:   "TKN",
+		Balances: map[thor.Address]*big.Int{holder: big.NewInt(100), owner: big.NewInt(50)},
+		Owner:    &owner,
+	})
+	assert.Equal(t, thor.BytesToBytes32(big.NewInt(100).Bytes()), storage[MappingSlot(Slot(0), thor.BytesToBytes32(holder.Bytes()))])
+	assert.Equal(t, Slot(150), storage[Slot(2)])
+	assert.Equal(t, thor.BytesToBytes32(owner.Bytes()), storage[Slot(5)])
+}
+
+func TestBuilderContracts(t *testing.T) {
+	artifact, err := ParseArtifact([]byte(`{"bytecode":"0x6080","deployedBytecode":"0x6001"}`))
+	require.NoError(t, err)
+	addr := thor.MustParseAddress("0x0000000000000000000000000000000000001000")
+
+	hayabusaTP := uint32(0)
+	gen := New(1).Config(&genesis.Config{HayabusaTP: &hayabusaTP}).Contracts(Contract{
+		Address:  addr,
+		Artifact: artifact,
+		Storage:  Storage{}.SetUint(0, big.NewInt(7)),
+		Balance:  big.NewInt(1),
+	}).Build()
+
+	account := gen.Accounts[len(gen.Accounts)-1]
+	assert.Equal(t, addr, account.Address)
+	assert.Equal(t, "0x6001", account.Code)
+	assert.Equal(t, Slot(7), account.Storage["0x0000000000000000000000000000000000000000000000000000000000000000"])
+	assert.Nil(t, account.Energy)
+
+	// Thor accepts the genesis
+	_, err = genesis.Check(gen, nil)
+	assert.NoError(t, err)
+}
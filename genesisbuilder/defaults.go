@@ -27,10 +27,18 @@ func DefaultAccounts() []genesis.Account {
 	return accounts
 }
 
+// DefaultStakers stakes the dev accounts, then the validators DeriveValidators(DefaultSeed, n) derives beyond them
 func DefaultStakers(n int) []genesis.Validator {
 	devAccounts := genesis.DevAccounts()
 	stakers := make([]genesis.Validator, n)
 	for i := range n {
+		if i >= len(devAccounts) {
+			stakers[i] = ValidatorKeys{
+				Master:   DeriveAccount(DefaultSeed, RoleMaster, i),
+				Endorser: DeriveAccount(DefaultSeed, RoleEndorser, i),
+			}.Validator()
+			continue
+		}
 		stakers[i] = genesis.Validator{
 			Master:   devAccounts[i].Address,
 			Endorser: devAccounts[i].Address,
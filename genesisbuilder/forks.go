@@ -0,0 +1,190 @@
+package genesisbuilder
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Fork names a thor hard fork
+type Fork string
+
+// Forks of the thor version networkhub is built with
+const (
+	VIP191    Fork = "VIP191"
+	ETHConst  Fork = "ETH_CONST"
+	Blocklist Fork = "BLOCKLIST"
+	ETHIST    Fork = "ETH_IST"
+	VIP214    Fork = "VIP214"
+	Finality  Fork = "FINALITY"
+	Galactica Fork = "GALACTICA"
+	Hayabusa  Fork = "HAYABUSA"
+)
+
+// defaultBlockInterval is thor's block interval in seconds when the genesis config does not set one
+const defaultBlockInterval = 10
+
+// forkField is a fork thor.ForkConfig knows and its field
+type forkField struct {
+	fork   Fork
+	height func(*thor.ForkConfig) *uint32
+}
+
+// forkHeights lists the forks thor.ForkConfig knows, in activation order
+var forkHeights = []forkField{
+	{VIP191, func(fc *thor.ForkConfig) *uint32 { return &fc.VIP191 }},
+	{ETHConst, func(fc *thor.ForkConfig) *uint32 { return &fc.ETH_CONST }},
+	{Blocklist, func(fc *thor.ForkConfig) *uint32 { return &fc.BLOCKLIST }},
+	{ETHIST, func(fc *thor.ForkConfig) *uint32 { return &fc.ETH_IST }},
+	{VIP214, func(fc *thor.ForkConfig) *uint32 { return &fc.VIP214 }},
+	{Finality, func(fc *thor.ForkConfig) *uint32 { return &fc.FINALITY }},
+	{Galactica, func(fc *thor.ForkConfig) *uint32 { return &fc.GALACTICA }},
+	{Hayabusa, func(fc *thor.ForkConfig) *uint32 { return &fc.HAYABUSA }},
+}
+
+// ForkSchedule schedules forks at block heights. Forks must activate in order, each at or after the
+// previous one, and forks left unscheduled are disabled, so every fork before a scheduled one must be scheduled too.
+type ForkSchedule struct {
+	forks   []Fork // forks the target thor knows, in activation order
+	heights map[Fork]uint32
+	errs    []error
+}
+
+// NewForkSchedule returns an empty schedule targeting the thor version networkhub is built with
+func NewForkSchedule() *ForkSchedule {
+	s := &ForkSchedule{heights: make(map[Fork]uint32)}
+	for _, f := range forkHeights {
+		s.forks = append(s.forks, f.fork)
+	}
+	return s
+}
+
+// Target restricts the schedule to an older thor whose latest fork is last. Thor rejects a genesis
+// with forks it does not know, so scheduling a later fork is an error. The later forks stay disabled in the
+// fork config, and are left out of the genesis file of nodes whose thor version predates them, see genesis.MarshalFor.
+func (s *ForkSchedule) Target(last Fork) *ForkSchedule {
+	i := slices.Index(s.forks, last)
+	if i < 0 {
+		s.errs = append(s.errs, fmt.Errorf("unknown target fork %s", last))
+		return s
+	}
+	s.forks = s.forks[:i+1]
+	return s
+}
+
+// Extend targets a newer thor that knows forks after the built-in ones, given in activation order.
+// They are written to the genesis through CustomGenesisForkConfig.AdditionalFields.
+func (s *ForkSchedule) Extend(forks ...Fork) *ForkSchedule {
+	for _, fork := range forks {
+		if slices.Contains(s.forks, fork) {
+			s.errs = append(s.errs, fmt.Errorf("fork %s is already known", fork))
+			continue
+		}
+		s.forks = append(s.forks, fork)
+	}
+	return s
+}
+
+// At schedules the fork at the given block height
+func (s *ForkSchedule) At(fork Fork, height uint32) *ForkSchedule {
+	s.heights[fork] = height
+	return s
+}
+
+// AllAt schedules every fork the target thor knows at the given block height, like thor.SoloFork at 0
+func (s *ForkSchedule) AllAt(height uint32) *ForkSchedule {
+	for _, fork := range s.forks {
+		s.heights[fork] = height
+	}
+	return s
+}
+
+// Validate checks every scheduled fork is known by the target thor and forks activate in order
+func (s *ForkSchedule) Validate() error {
+	errs := append([]error{}, s.errs...)
+
+	for fork := range s.heights {
+		if !slices.Contains(s.forks, fork) {
+			errs = append(errs, fmt.Errorf("fork %s is unknown to the target thor, which knows %v", fork, s.forks))
+		}
+	}
+
+	// Compare each scheduled fork with the latest fork before it, unscheduled forks never activate
+	var previous Fork
+	for _, fork := range s.forks {
+		height, scheduled := s.heights[fork]
+		if previous != "" {
+			previousHeight, previousScheduled := s.heights[previous]
+			switch {
+			case scheduled && !previousScheduled:
+				errs = append(errs, fmt.Errorf("fork %s at block %d comes after %s, which is not scheduled", fork, height, previous))
+			case scheduled && height < previousHeight:
+				errs = append(errs, fmt.Errorf("fork %s at block %d is before %s at block %d", fork, height, previous, previousHeight))
+			}
+		}
+		previous = fork
+	}
+
+	return errors.Join(errs...)
+}
+
+// Build validates the schedule and returns the genesis fork config, with unscheduled forks disabled
+func (s *ForkSchedule) Build() (*genesis.CustomGenesisForkConfig, error) {
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fork schedule: %w", err)
+	}
+
+	forkConfig := genesis.NewCustomGenesisForkConfig(thor.NoFork)
+	for _, f := range forkHeights {
+		if height, ok := s.heights[f.fork]; ok {
+			*f.height(&forkConfig.ForkConfig) = height
+		}
+	}
+	for _, fork := range s.forks {
+		if height, ok := s.heights[fork]; ok && !builtIn(fork) {
+			if err := forkConfig.AddField(string(fork), height); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return forkConfig, nil
+}
+
+// builtIn tells whether the fork is one of thor.ForkConfig's
+func builtIn(fork Fork) bool {
+	return slices.ContainsFunc(forkHeights, func(f forkField) bool { return f.fork == fork })
+}
+
+// ForkActivation is when a scheduled fork activates
+type ForkActivation struct {
+	Fork   Fork
+	Height uint32
+	After  time.Duration // time from the genesis launch to the block
+	Time   time.Time     // time of the block
+}
+
+// Activations returns when each scheduled fork activates on a network launched with the genesis, in activation
+// order. Blocks are produced every block interval of the genesis config, thor's default of 10s if unset,
+// so the times hold for a network that misses no slot.
+func (s *ForkSchedule) Activations(gen *genesis.CustomGenesis) []ForkActivation {
+	interval := uint64(defaultBlockInterval)
+	if gen.Config != nil && gen.Config.BlockInterval > 0 {
+		interval = gen.Config.BlockInterval
+	}
+	launch := time.Unix(int64(gen.LaunchTime), 0)
+
+	var activations []ForkActivation
+	for _, fork := range s.forks {
+		height, ok := s.heights[fork]
+		if !ok {
+			continue
+		}
+		after := time.Duration(uint64(height)*interval) * time.Second
+		activations = append(activations, ForkActivation{Fork: fork, Height: height, After: after, Time: launch.Add(after)})
+	}
+	return activations
+}
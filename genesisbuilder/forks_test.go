@@ -0,0 +1,88 @@
+package genesisbuilder
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestForkSchedule(t *testing.T) {
+	forkConfig, err := NewForkSchedule().AllAt(0).Build()
+	require.NoError(t, err)
+	assert.Equal(t, thor.SoloFork, forkConfig.ForkConfig)
+
+	forkConfig, err = NewForkSchedule().AllAt(0).At(Galactica, 5).At(Hayabusa, 20).Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), forkConfig.FINALITY)
+	assert.Equal(t, uint32(5), forkConfig.GALACTICA)
+	assert.Equal(t, uint32(20), forkConfig.HAYABUSA)
+
+	// Unscheduled forks are disabled
+	forkConfig, err = NewForkSchedule().At(VIP191, 0).At(ETHConst, 1).Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), forkConfig.ETH_CONST)
+	assert.Equal(t, uint32(math.MaxUint32), forkConfig.HAYABUSA)
+
+	t.Run("Ordering", func(t *testing.T) {
+		_, err := NewForkSchedule().AllAt(0).At(Finality, 10).At(Galactica, 5).Build()
+		assert.ErrorContains(t, err, "fork GALACTICA at block 5 is before FINALITY at block 10")
+
+		_, err = NewForkSchedule().At(VIP191, 0).At(Blocklist, 0).Build()
+		assert.ErrorContains(t, err, "fork BLOCKLIST at block 0 comes after ETH_CONST, which is not scheduled")
+	})
+
+	t.Run("Target", func(t *testing.T) {
+		_, err := NewForkSchedule().Target(Galactica).AllAt(0).At(Hayabusa, 10).Build()
+		assert.ErrorContains(t, err, "fork HAYABUSA is unknown to the target thor")
+
+		_, err = NewForkSchedule().At("VIP999", 10).Build()
+		assert.ErrorContains(t, err, "fork VIP999 is unknown to the target thor")
+
+		_, err = NewForkSchedule().Target("VIP999").Build()
+		assert.ErrorContains(t, err, "unknown target fork VIP999")
+
+		// Forks the target does not know are left out of the genesis file of its release
+		forkConfig, err := NewForkSchedule().Target(Galactica).AllAt(0).Build()
+		require.NoError(t, err)
+		data, err := genesis.MarshalFor(&genesis.CustomGenesis{ForkConfig: forkConfig}, "v2.3.0")
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"GALACTICA":0`)
+		assert.NotContains(t, string(data), "HAYABUSA")
+	})
+
+	t.Run("Extend", func(t *testing.T) {
+		forkConfig, err := NewForkSchedule().Extend("INTERSTELLAR").AllAt(0).At("INTERSTELLAR", 30).Build()
+		require.NoError(t, err)
+		height, ok := forkConfig.GetField("INTERSTELLAR")
+		assert.True(t, ok)
+		assert.Equal(t, uint32(30), height)
+
+		// networkhub's thor checks the genesis without the forks it does not know
+		_, _, err = genesis.ToThor(New(1).ForkConfig(forkConfig).Build())
+		assert.NoError(t, err)
+
+		_, err = NewForkSchedule().Extend(Hayabusa).Build()
+		assert.ErrorContains(t, err, "fork HAYABUSA is already known")
+	})
+}
+
+func TestForkScheduleActivations(t *testing.T) {
+	schedule := NewForkSchedule().AllAt(0).At(Galactica, 6).At(Hayabusa, 30)
+	gen := New(1).Config(&genesis.Config{BlockInterval: 2}).Build()
+
+	activations := schedule.Activations(gen)
+	require.Len(t, activations, 8)
+	assert.Equal(t, ForkActivation{Fork: VIP191, Height: 0, After: 0, Time: time.Unix(int64(gen.LaunchTime), 0)}, activations[0])
+	assert.Equal(t, Hayabusa, activations[7].Fork)
+	assert.Equal(t, time.Minute, activations[7].After)
+	assert.Equal(t, time.Unix(int64(gen.LaunchTime), 0).Add(12*time.Second), activations[6].Time)
+
+	// Thor's default block interval applies when the config leaves it unset
+	gen.Config = nil
+	assert.Equal(t, 5*time.Minute, schedule.Activations(gen)[7].After)
+}
@@ -0,0 +1,143 @@
+package genesisbuilder
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/networkhub/utils/common"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Roles of the accounts derived for each validator
+const (
+	RoleMaster      = "master"
+	RoleEndorser    = "endorser"
+	RoleBeneficiary = "beneficiary"
+)
+
+// DefaultSeed derives the stakers DefaultStakers adds beyond the dev accounts
+const DefaultSeed = "networkhub"
+
+// ValidatorKeys holds the accounts of a validator: the master key signs blocks and is the node key,
+// the endorser stakes for the validator and the beneficiary receives its rewards
+type ValidatorKeys struct {
+	Master      *common.Account
+	Endorser    *common.Account
+	Beneficiary *common.Account
+}
+
+// DeriveValidators derives the accounts of n validators from a seed, any string such as a mnemonic.
+// The same seed always gives the same accounts, and growing n keeps the first validators unchanged.
+// Keys are derived with keccak256 rather than BIP-32, so they differ from wallets using the same mnemonic.
+func DeriveValidators(seed string, n int) []ValidatorKeys {
+	validators := make([]ValidatorKeys, n)
+	for i := range validators {
+		validators[i] = ValidatorKeys{
+			Master:      DeriveAccount(seed, RoleMaster, i),
+			Endorser:    DeriveAccount(seed, RoleEndorser, i),
+			Beneficiary: DeriveAccount(seed, RoleBeneficiary, i),
+		}
+	}
+	return validators
+}
+
+// DeriveAccount derives the account of the given role and index from a seed.
+// Whitespace in the seed is normalized, so mnemonics may be wrapped or indented.
+func DeriveAccount(seed, role string, index int) *common.Account {
+	seed = strings.Join(strings.Fields(seed), " ")
+	for counter := byte(0); ; counter++ {
+		hash := crypto.Keccak256(
+			[]byte(seed), []byte{0},
+			[]byte(role), []byte{0},
+			binary.BigEndian.AppendUint32(nil, uint32(index)),
+			[]byte{counter},
+		)
+		// Hashes that are not a valid secp256k1 key are vanishingly rare, the counter moves past them
+		if key, err := crypto.ToECDSA(hash); err == nil {
+			return newAccount(key)
+		}
+	}
+}
+
+// Validator returns the genesis staker entry of the validator
+func (v ValidatorKeys) Validator() thorgenesis.Validator {
+	return thorgenesis.Validator{Master: *v.Master.Address, Endorser: *v.Endorser.Address}
+}
+
+// Authority returns the genesis authority entry of the validator, its identity derived from the master address
+func (v ValidatorKeys) Authority() thorgenesis.Authority {
+	return thorgenesis.Authority{
+		MasterAddress:   *v.Master.Address,
+		EndorsorAddress: *v.Endorser.Address,
+		Identity:        thor.Blake2b(v.Master.Address.Bytes()),
+	}
+}
+
+// Node returns a node config keyed with the validator's master key and paying rewards to its beneficiary.
+// Ports and addresses are left to the caller.
+func (v ValidatorKeys) Node(id string, gen *genesis.CustomGenesis) *node.BaseNode {
+	return &node.BaseNode{
+		ID:             id,
+		Key:            v.Master.PrivateKeyString(),
+		Genesis:        gen,
+		AdditionalArgs: map[string]string{"beneficiary": v.Beneficiary.Address.String()},
+	}
+}
+
+// Validators stakes the validators in the genesis and funds their master and endorser accounts,
+// on top of the accounts set with Accounts or the default ones. Each call adds to the stakers.
+func (b *Builder) Validators(validators []ValidatorKeys) *Builder {
+	b.validators = append(b.validators, validators...)
+	for _, validator := range validators {
+		b.stakers = append(b.stakers, validator.Validator())
+	}
+	return b
+}
+
+// AuthorityValidators makes the validators the proof-of-authority nodes of the genesis and funds their
+// master and endorser accounts. With the HAYABUSA fork after genesis, they propose blocks until they
+// stake and the network transitions to PoS. Each call adds to the authority nodes.
+func (b *Builder) AuthorityValidators(validators []ValidatorKeys) *Builder {
+	b.validators = append(b.validators, validators...)
+	for _, validator := range validators {
+		b.authority = append(b.authority, validator.Authority())
+	}
+	return b
+}
+
+// validatorAccounts returns the master and endorser accounts of the validators, funded with 10 billion
+// VET and VTHO or the proposer endorsement if larger
+func (b *Builder) validatorAccounts(validators []ValidatorKeys) []thorgenesis.Account {
+	balance := new(big.Int).Mul(big.NewInt(10e9), big.NewInt(1e18))
+	if endorsement := (*big.Int)(b.params.ProposerEndorsement); endorsement != nil && endorsement.Cmp(balance) > 0 {
+		balance = endorsement
+	}
+
+	var accounts []thorgenesis.Account
+	funded := make(map[thor.Address]bool)
+	for _, validator := range validators {
+		for _, addr := range []*thor.Address{validator.Master.Address, validator.Endorser.Address} {
+			if funded[*addr] {
+				continue
+			}
+			funded[*addr] = true
+			accounts = append(accounts, thorgenesis.Account{
+				Address: *addr,
+				Balance: (*thorgenesis.HexOrDecimal256)(balance),
+				Energy:  (*thorgenesis.HexOrDecimal256)(balance),
+			})
+		}
+	}
+	return accounts
+}
+
+func newAccount(key *ecdsa.PrivateKey) *common.Account {
+	addr := thor.Address(crypto.PubkeyToAddress(key.PublicKey))
+	return &common.Account{Address: &addr, PrivateKey: key}
+}
@@ -0,0 +1,95 @@
+package genesisbuilder
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/network/node/genesis"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestDeriveValidators(t *testing.T) {
+	validators := DeriveValidators("test test test", 50)
+	require.Len(t, validators, 50)
+
+	// Deterministic, whitespace-insensitive and stable as the network grows
+	again := DeriveValidators("  test\ntest   test ", 3)
+	for i := range again {
+		assert.Equal(t, validators[i].Master.Address, again[i].Master.Address)
+	}
+	assert.NotEqual(t, validators[0].Master.Address, DeriveValidators("other", 1)[0].Master.Address)
+
+	seen := make(map[thor.Address]bool)
+	for _, validator := range validators {
+		for _, account := range []*thor.Address{validator.Master.Address, validator.Endorser.Address, validator.Beneficiary.Address} {
+			assert.False(t, seen[*account], "duplicate account %s", account)
+			seen[*account] = true
+		}
+	}
+
+	// The node key matches the staked master
+	nodeCfg := validators[7].Node("node8", nil)
+	key, err := crypto.HexToECDSA(nodeCfg.GetKey())
+	require.NoError(t, err)
+	assert.Equal(t, validators[7].Validator().Master, thor.Address(crypto.PubkeyToAddress(key.PublicKey)))
+	assert.Equal(t, validators[7].Beneficiary.Address.String(), nodeCfg.GetAdditionalArgs()["beneficiary"])
+}
+
+func TestBuilderValidators(t *testing.T) {
+	validators := DeriveValidators("seed", 20)
+	gen := New(20).Validators(validators).Build()
+
+	require.Len(t, gen.Stakers, 20)
+	assert.Equal(t, validators[19].Validator(), gen.Stakers[19])
+
+	funded := make(map[thor.Address]bool)
+	for _, account := range gen.Accounts {
+		funded[account.Address] = true
+	}
+	for _, validator := range validators {
+		assert.True(t, funded[*validator.Master.Address])
+		assert.True(t, funded[*validator.Endorser.Address])
+	}
+	// Default accounts are kept
+	assert.Len(t, gen.Accounts, len(DefaultAccounts())+40)
+
+	// Validators added in several calls are all staked and funded
+	gen = New(20).Validators(validators[:12]).Validators(validators[12:]).Build()
+	require.Len(t, gen.Stakers, 20)
+	assert.Equal(t, validators[0].Validator(), gen.Stakers[0])
+	assert.Len(t, gen.Accounts, len(DefaultAccounts())+40)
+
+	// Default stakers go beyond the dev accounts
+	stakers := DefaultStakers(12)
+	assert.Equal(t, DeriveValidators(DefaultSeed, 12)[11].Validator(), stakers[11])
+}
+
+func TestBuilderFundsDefaultStakers(t *testing.T) {
+	// Stakers beyond the dev accounts are derived, thor only builds the genesis when their endorsers can stake
+	zero := uint32(0)
+	_, err := genesis.Check(New(12).Config(&genesis.Config{HayabusaTP: &zero}).Build(), nil)
+	require.NoError(t, err)
+}
+
+func TestBuilderAuthority(t *testing.T) {
+	validators := DeriveValidators("authority", 3)
+	gen := New(3).AuthorityValidators(validators).Validators(validators[:1]).Build()
+
+	require.Len(t, gen.Authority, 3)
+	assert.Equal(t, *validators[2].Master.Address, gen.Authority[2].MasterAddress)
+	assert.Equal(t, *validators[2].Endorser.Address, gen.Authority[2].EndorsorAddress)
+	assert.NotEqual(t, thor.Bytes32{}, gen.Authority[2].Identity)
+	assert.Len(t, gen.Stakers, 1)
+	// Validators both authority and staker are funded once
+	assert.Len(t, gen.Accounts, len(DefaultAccounts())+6)
+
+	gen = New(3).AuthorityValidators(validators[:1]).AuthorityValidators(validators[1:]).Build()
+	require.Len(t, gen.Authority, 3)
+	assert.Equal(t, *validators[0].Master.Address, gen.Authority[0].MasterAddress)
+
+	// Authority nodes replace the default stakers
+	assert.Empty(t, New(1).Authority([]thorgenesis.Authority{validators[0].Authority()}).Build().Stakers)
+}
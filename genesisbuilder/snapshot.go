@@ -0,0 +1,278 @@
+package genesisbuilder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// snapshotColumns are the CSV columns of a snapshot, code and storage are optional
+var snapshotColumns = []string{"address", "vet", "vtho", "code", "storage"}
+
+// decimals of VET and VTHO
+const decimals = 18
+
+// snapshotAccount is an account of a snapshot, amounts are in VET and VTHO
+type snapshotAccount struct {
+	Address thor.Address            `json:"address"`
+	VET     string                  `json:"vet"`
+	VTHO    string                  `json:"vtho"`
+	Code    string                  `json:"code,omitempty"`
+	Storage map[string]thor.Bytes32 `json:"storage,omitempty"`
+}
+
+// LoadSnapshot reads genesis accounts from a CSV or JSON snapshot, told apart by the file extension.
+// Both hold the address, VET and VTHO of each account, and optionally its code and storage:
+//
+//	address,vet,vtho,code,storage
+//	0x7567d83b7b8d80addcb281a71d54fc7b3364ffed,1000.5,20,,
+//
+// The JSON snapshot is an array of {"address", "vet", "vtho", "code", "storage"} objects. Amounts are in
+// VET and VTHO with up to 18 decimals, and CSV storage is a JSON object of slots to values.
+// Duplicate addresses are an error.
+func LoadSnapshot(path string) ([]thorgenesis.Account, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	var accounts []thorgenesis.Account
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		accounts, err = ParseSnapshotCSV(f)
+	case ".json":
+		accounts, err = ParseSnapshotJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported snapshot format %q, expected .csv or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return accounts, nil
+}
+
+// ParseSnapshotCSV reads genesis accounts from a CSV snapshot with a header row, see LoadSnapshot
+func ParseSnapshotCSV(r io.Reader) ([]thorgenesis.Account, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(snapshotColumns, name) {
+			return nil, fmt.Errorf("unknown column %q, expected %s", name, strings.Join(snapshotColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, name := range snapshotColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var entries []snapshotAccount
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		addr, err := thor.ParseAddress(field("address"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: address: %w", row, err)
+		}
+		entry := snapshotAccount{Address: addr, VET: field("vet"), VTHO: field("vtho"), Code: field("code")}
+		if storage := field("storage"); storage != "" {
+			if err := json.Unmarshal([]byte(storage), &entry.Storage); err != nil {
+				return nil, fmt.Errorf("row %d: storage: %w", row, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return toAccounts(entries, "row", 2)
+}
+
+// ParseSnapshotJSON reads genesis accounts from a JSON snapshot, see LoadSnapshot
+func ParseSnapshotJSON(r io.Reader) ([]thorgenesis.Account, error) {
+	var entries []snapshotAccount
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return toAccounts(entries, "entry", 1)
+}
+
+// toAccounts converts snapshot entries to genesis accounts, reporting invalid amounts and duplicate addresses
+// by their position, counted from first
+func toAccounts(entries []snapshotAccount, position string, first int) ([]thorgenesis.Account, error) {
+	var errs []error
+	seen := make(map[thor.Address]int)
+	accounts := make([]thorgenesis.Account, 0, len(entries))
+	for i, entry := range entries {
+		pos := first + i
+		if previous, ok := seen[entry.Address]; ok {
+			errs = append(errs, fmt.Errorf("duplicate address %s in %ss %d and %d", entry.Address, position, previous, pos))
+			continue
+		}
+		seen[entry.Address] = pos
+
+		balance, err := parseAmount(entry.VET)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %d: vet: %w", position, pos, err))
+			continue
+		}
+		energy, err := parseAmount(entry.VTHO)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %d: vtho: %w", position, pos, err))
+			continue
+		}
+		code := entry.Code
+		if code != "" && !strings.HasPrefix(code, "0x") {
+			code = "0x" + code
+		}
+		accounts = append(accounts, thorgenesis.Account{
+			Address: entry.Address,
+			Balance: (*thorgenesis.HexOrDecimal256)(balance),
+			Energy:  (*thorgenesis.HexOrDecimal256)(energy),
+			Code:    code,
+			Storage: entry.Storage,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return accounts, nil
+}
+
+// WriteSnapshot writes the accounts as a CSV or JSON snapshot, told apart by the file extension, see LoadSnapshot
+func WriteSnapshot(path string, accounts []thorgenesis.Account) error {
+	entries := make([]snapshotAccount, len(accounts))
+	for i, account := range accounts {
+		entries[i] = snapshotAccount{
+			Address: account.Address,
+			VET:     formatAmount((*big.Int)(account.Balance)),
+			VTHO:    formatAmount((*big.Int)(account.Energy)),
+			Code:    account.Code,
+			Storage: account.Storage,
+		}
+	}
+
+	var data []byte
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		var buf strings.Builder
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write(snapshotColumns)
+		for _, entry := range entries {
+			storage := ""
+			if len(entry.Storage) > 0 {
+				encoded, err := json.Marshal(entry.Storage)
+				if err != nil {
+					return err
+				}
+				storage = string(encoded)
+			}
+			_ = writer.Write([]string{entry.Address.String(), entry.VET, entry.VTHO, entry.Code, storage})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		data = []byte(buf.String())
+	case ".json":
+		encoded, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = encoded
+	default:
+		return fmt.Errorf("unsupported snapshot format %q, expected .csv or .json", ext)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshot adds the accounts of a snapshot to the accounts set with Accounts or the default ones.
+// A snapshot account replaces an account at the same address.
+func (b *Builder) Snapshot(accounts []thorgenesis.Account) *Builder {
+	b.snapshot = accounts
+	return b
+}
+
+// MergeAccounts returns the base accounts with the overrides applied: an override replaces the base account
+// at the same address, in place, and the other overrides are appended
+func MergeAccounts(base, overrides []thorgenesis.Account) []thorgenesis.Account {
+	merged := append([]thorgenesis.Account{}, base...)
+	index := make(map[thor.Address]int, len(merged))
+	for i, account := range merged {
+		index[account.Address] = i
+	}
+	for _, account := range overrides {
+		if i, ok := index[account.Address]; ok {
+			merged[i] = account
+			continue
+		}
+		index[account.Address] = len(merged)
+		merged = append(merged, account)
+	}
+	return merged
+}
+
+// parseAmount converts an amount of VET or VTHO to wei, empty is zero
+func parseAmount(amount string) (*big.Int, error) {
+	if amount == "" {
+		return new(big.Int), nil
+	}
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("%s has more than %d decimals", amount, decimals)
+	}
+	wei, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, fmt.Errorf("%s is not a positive amount", amount)
+	}
+	return wei, nil
+}
+
+// formatAmount converts wei to an amount of VET or VTHO, nil is zero
+func formatAmount(wei *big.Int) string {
+	if wei == nil {
+		return "0"
+	}
+	digits := wei.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals+1-len(digits)) + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
@@ -0,0 +1,87 @@
+package genesisbuilder
+
+import (
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestParseSnapshot(t *testing.T) {
+	accounts, err := ParseSnapshotCSV(strings.NewReader(`address, vet, vtho, storage
+0x7567d83b7b8d80addcb281a71d54fc7b3364ffed, 1000.5, 20, "{""0x0000000000000000000000000000000000000000000000000000000000000001"":""0x0000000000000000000000000000000000000000000000000000000000000002""}"
+0x0000000000000000000000000000000000001000, 0.000000000000000001, ,
+`))
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "1000500000000000000000", (*big.Int)(accounts[0].Balance).String())
+	assert.Equal(t, "20000000000000000000", (*big.Int)(accounts[0].Energy).String())
+	assert.Equal(t, Slot(2), accounts[0].Storage[Slot(1).String()])
+	assert.Equal(t, big.NewInt(1), (*big.Int)(accounts[1].Balance))
+	assert.Equal(t, new(big.Int), (*big.Int)(accounts[1].Energy))
+
+	accounts, err = ParseSnapshotJSON(strings.NewReader(`[{"address":"0x0000000000000000000000000000000000001000","vet":"1","vtho":"2","code":"6001"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, "0x6001", accounts[0].Code)
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := ParseSnapshotCSV(strings.NewReader(`address,vet,vtho
+0x0000000000000000000000000000000000001000,1,1
+0x0000000000000000000000000000000000002000,1,1
+0x0000000000000000000000000000000000001000,2,2
+0x0000000000000000000000000000000000002000,1.0000000000000000001,1
+`))
+		assert.ErrorContains(t, err, "duplicate address 0x0000000000000000000000000000000000001000 in rows 2 and 4")
+		assert.ErrorContains(t, err, "duplicate address 0x0000000000000000000000000000000000002000 in rows 3 and 5")
+
+		_, err = ParseSnapshotJSON(strings.NewReader(`[{"address":"0x0000000000000000000000000000000000001000","vet":"-1"}]`))
+		assert.ErrorContains(t, err, "entry 1: vet: -1 is not a positive amount")
+
+		_, err = ParseSnapshotCSV(strings.NewReader("address,vet\n"))
+		assert.ErrorContains(t, err, `missing column "vtho"`)
+
+		_, err = LoadSnapshot("accounts.txt")
+		assert.ErrorContains(t, err, "unable to open snapshot")
+	})
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	accounts := []thorgenesis.Account{
+		{
+			Address: thor.MustParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed"),
+			Balance: (*thorgenesis.HexOrDecimal256)(big.NewInt(1_500_000_000_000_000_000)),
+			Energy:  (*thorgenesis.HexOrDecimal256)(big.NewInt(3)),
+		},
+		{
+			Address: thor.MustParseAddress("0x0000000000000000000000000000000000001000"),
+			Balance: (*thorgenesis.HexOrDecimal256)(new(big.Int)),
+			Energy:  (*thorgenesis.HexOrDecimal256)(new(big.Int)),
+			Code:    "0x6001",
+			Storage: map[string]thor.Bytes32{Slot(0).String(): Slot(7)},
+		},
+	}
+
+	for _, name := range []string{"snapshot.csv", "snapshot.json"} {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, WriteSnapshot(path, accounts))
+		loaded, err := LoadSnapshot(path)
+		require.NoError(t, err)
+		assert.Equal(t, accounts, loaded, name)
+	}
+}
+
+func TestBuilderSnapshot(t *testing.T) {
+	defaults := DefaultAccounts()
+	override := thorgenesis.Account{Address: defaults[1].Address, Balance: (*thorgenesis.HexOrDecimal256)(big.NewInt(1))}
+	added := thorgenesis.Account{Address: thor.MustParseAddress("0x0000000000000000000000000000000000001000")}
+
+	gen := New(1).Snapshot([]thorgenesis.Account{override, added}).Build()
+	require.Len(t, gen.Accounts, len(defaults)+1)
+	assert.Equal(t, override, gen.Accounts[1])
+	assert.Equal(t, added, gen.Accounts[len(defaults)])
+}
@@ -0,0 +1,102 @@
+package genesisbuilder
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Storage holds the storage slots of a contract. Its setters follow Solidity's storage layout, where state
+// variables take consecutive slots from 0 in declaration order, base contracts first.
+type Storage map[thor.Bytes32]thor.Bytes32
+
+// Set sets a slot to a raw value
+func (s Storage) Set(slot thor.Bytes32, value thor.Bytes32) Storage {
+	s[slot] = value
+	return s
+}
+
+// SetUint sets a uint256 state variable
+func (s Storage) SetUint(slot uint64, value *big.Int) Storage {
+	return s.Set(Slot(slot), thor.BytesToBytes32(value.Bytes()))
+}
+
+// SetAddress sets an address state variable, such as the owner of an Ownable contract
+func (s Storage) SetAddress(slot uint64, addr thor.Address) Storage {
+	return s.Set(Slot(slot), thor.BytesToBytes32(addr.Bytes()))
+}
+
+// SetString sets a string or bytes state variable. Values of up to 31 bytes are stored in the slot itself,
+// longer ones in the slots from keccak256(slot) on.
+func (s Storage) SetString(slot uint64, value string) Storage {
+	if len(value) < 32 {
+		var packed thor.Bytes32
+		copy(packed[:], value)
+		packed[31] = byte(len(value) * 2)
+		return s.Set(Slot(slot), packed)
+	}
+
+	s.SetUint(slot, big.NewInt(int64(len(value)*2+1)))
+	data := new(big.Int).SetBytes(thor.Keccak256(Slot(slot).Bytes()).Bytes())
+	for i := 0; i < len(value); i += 32 {
+		var chunk thor.Bytes32
+		copy(chunk[:], value[i:])
+		s.Set(thor.BytesToBytes32(data.Bytes()), chunk)
+		data.Add(data, big.NewInt(1))
+	}
+	return s
+}
+
+// SetMapping sets the value of key in a mapping state variable
+func (s Storage) SetMapping(slot uint64, key thor.Bytes32, value thor.Bytes32) Storage {
+	return s.Set(MappingSlot(Slot(slot), key), value)
+}
+
+// Slot returns the storage slot of the given index
+func Slot(index uint64) thor.Bytes32 {
+	return thor.BytesToBytes32(new(big.Int).SetUint64(index).Bytes())
+}
+
+// MappingSlot returns the slot of key in the mapping at slot, keccak256(key . slot). For nested mappings,
+// pass the slot of the outer key as the slot of the inner mapping.
+func MappingSlot(slot thor.Bytes32, key thor.Bytes32) thor.Bytes32 {
+	return thor.Keccak256(key.Bytes(), slot.Bytes())
+}
+
+// ERC20Layout gives the slots of an ERC20 token's state variables
+type ERC20Layout struct {
+	Balances    uint64 // mapping(address => uint256)
+	TotalSupply uint64
+	Name        uint64
+	Symbol      uint64
+	Owner       uint64 // address of Ownable, used when ERC20Token.Owner is set
+}
+
+// OpenZeppelinERC20 is the layout of a token deriving from OpenZeppelin's ERC20 and then Ownable,
+// as in `contract Token is ERC20, Ownable`
+var OpenZeppelinERC20 = ERC20Layout{Balances: 0, TotalSupply: 2, Name: 3, Symbol: 4, Owner: 5}
+
+// ERC20Token is the state of an ERC20 token at genesis
+type ERC20Token struct {
+	Name     string
+	Symbol   string
+	Balances map[thor.Address]*big.Int
+	Owner    *thor.Address // nil for tokens without an owner
+}
+
+// Storage returns the storage of the token, its total supply the sum of the balances
+func (l ERC20Layout) Storage(token ERC20Token) Storage {
+	storage := Storage{}
+	totalSupply := new(big.Int)
+	for addr, balance := range token.Balances {
+		storage.SetMapping(l.Balances, thor.BytesToBytes32(addr.Bytes()), thor.BytesToBytes32(balance.Bytes()))
+		totalSupply.Add(totalSupply, balance)
+	}
+	storage.SetUint(l.TotalSupply, totalSupply)
+	storage.SetString(l.Name, token.Name)
+	storage.SetString(l.Symbol, token.Symbol)
+	if token.Owner != nil {
+		storage.SetAddress(l.Owner, *token.Owner)
+	}
+	return storage
+}
@@ -1,6 +1,8 @@
 package environments
 
 import (
+	"io"
+
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
 )
@@ -12,6 +14,17 @@ type Actions interface {
 	Config() *network.Network
 	AddNode(nodeConfig node.Config) error
 	RemoveNode(nodeID string) error
+	StopNode(nodeID string) error
+	StartNode(nodeID string) error
+	PauseNode(nodeID string) error
+	ResumeNode(nodeID string) error
+	Exec(nodeID string, argv []string) (*node.ExecResult, error)
+	CopyFrom(nodeID, path string) (io.ReadCloser, error)
+	CopyTo(nodeID, path string, archive io.Reader) error
+	Supports(capability Capability) bool
+	Partition(groups ...[]string) error
+	Heal() error
+	ShapeLinks(shapes ...network.LinkShape) error
 }
 
 // Environment types define how nodes are executed and managed
@@ -21,6 +34,12 @@ const (
 
 	// Docker environment runs nodes in Docker containers
 	Docker = "docker"
+
+	// InProcess environment runs nodes inside the current process
+	InProcess = "inprocess"
+
+	// Fake environment serves a scripted thor API without running thor, for unit tests
+	Fake = "fake"
 )
 
 // Thor binary network arguments used when executing thor nodes
@@ -1,10 +1,13 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
@@ -14,6 +17,7 @@ import (
 	"github.com/docker/go-connections/nat"
 	"github.com/vechain/networkhub/network/node"
 	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/networkhub/thorbuilder"
 )
 
 // NewDockerNode initializes a new DockerNode
@@ -35,6 +39,9 @@ type Node struct {
 	networkID    string
 	exposedPorts *ExposedPort
 	ipAddr       string
+	allowedPeers bool   // pass enodes as --allowed-peers to pin a declared topology
+	gateway      string // Docker network gateway, reaching the host where HostGatewayName does not resolve
+	thorArgs     []string
 }
 
 // Start runs the node as a Docker container
@@ -45,41 +52,20 @@ func (n *Node) Start() error {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
-	// Check if the Docker image is available locally
-	_, err = cli.ImageInspect(ctx, n.cfg.GetExecArtifact())
-	if err != nil {
-		if client.IsErrNotFound(err) {
-			// Pull the Docker image
-			out, err := cli.ImagePull(ctx, n.cfg.GetExecArtifact(), image.PullOptions{})
-			if err != nil {
-				return fmt.Errorf("failed to pull Docker image: %w", err)
-			}
-			defer out.Close()
-
-			// We wait for the image to be pulled
-			decoder := json.NewDecoder(out)
-			for {
-				var event map[string]interface{}
-				if err := decoder.Decode(&event); err == io.EOF {
-					break
-				} else if err != nil {
-					return fmt.Errorf("failed to decode image pull event: %w", err)
-				}
-			}
-		} else {
-			return fmt.Errorf("failed to inspect Docker image: %w", err)
-		}
+	if err := ensureImage(ctx, cli, n.cfg.GetExecArtifact()); err != nil {
+		return err
 	}
 
 	cleanEnode := n.cleanEnodes()
 
 	// Build thor command arguments
 	thorArgs := n.buildThorArgs(cleanEnode)
+	n.thorArgs = thorArgs
 
-	// Build the full shell command
+	// Build the full shell command, resolving the host of bootnodes of other environments in the container
 	shellCommands := []string{
+		fmt.Sprintf("HOST_IP=$(getent hosts %s | cut -d' ' -f1); HOST_IP=${HOST_IP:-%s}", HostGatewayName, n.gateway),
 		"cd /home/thor",
-		"echo $GENESIS > genesis.json",
 		"echo $PRIVATEKEY > master.key",
 		"echo $PRIVATEKEY > p2p.key",
 		strings.Join(thorArgs, " "),
@@ -88,7 +74,7 @@ func (n *Node) Start() error {
 	cmd := []string{"sh", "-c", strings.Join(shellCommands, "; ")}
 
 	//serialize genesis
-	genesisBytes, err := nodegenesis.Marshal(n.cfg.GetGenesis())
+	genesisBytes, err := nodegenesis.MarshalFor(n.cfg.GetGenesis(), n.cfg.GetThorVersion())
 	if err != nil {
 		return fmt.Errorf("unable to marshal genesis - %w", err)
 	}
@@ -104,21 +90,43 @@ func (n *Node) Start() error {
 		},
 	}
 
+	// Publish the p2p port so nodes of other environments can dial the container through the host
+	if p2pPort := n.exposedPorts.P2PPort; p2pPort != "" {
+		for _, proto := range []string{"tcp", "udp"} {
+			port := nat.Port(fmt.Sprintf("%s/%s", p2pPort, proto))
+			exposedPorts[port] = struct{}{}
+			portBindings[port] = []nat.PortBinding{{HostPort: n.exposedPorts.HostP2PPort}}
+		}
+	}
+
+	if port := n.metricsPort(); port != "" {
+		metricsPort := nat.Port(port + "/tcp")
+		exposedPorts[metricsPort] = struct{}{}
+		portBindings[metricsPort] = []nat.PortBinding{{HostPort: port}}
+	}
+
+	env := []string{
+		fmt.Sprintf("PRIVATEKEY=%s", n.cfg.GetKey()),
+	}
+	// Skew the clock of a thor built with a skewable clock
+	if skew := n.cfg.GetClockSkew(); skew != nil {
+		env = append(env, thorbuilder.ClockSkewEnv(skew.Offset, skew.Drift)...)
+	}
+
 	// Construct Docker container configuration
 	config := &container.Config{
-		Image:      n.cfg.GetExecArtifact(),
-		Cmd:        cmd,
-		Entrypoint: []string{},
-		Env: []string{
-			fmt.Sprintf("GENESIS=%s", string(genesisBytes)),
-			fmt.Sprintf("PRIVATEKEY=%s", n.cfg.GetKey()),
-		},
+		Image:        n.cfg.GetExecArtifact(),
+		Cmd:          cmd,
+		Entrypoint:   []string{},
+		Env:          env,
 		ExposedPorts: exposedPorts,
 		Hostname:     fmt.Sprintf("thor-%s", n.cfg.GetID()),
 	}
 
 	hostConfig := &container.HostConfig{
 		PortBindings: portBindings,
+		// Docker Desktop resolves host.docker.internal already, Linux daemons need it mapped
+		ExtraHosts: []string{HostGatewayName + ":host-gateway"},
 	}
 
 	// Define the network configuration
@@ -140,6 +148,15 @@ func (n *Node) Start() error {
 
 	n.id = resp.ID
 
+	// Environment variables are capped at 128 KiB, so the genesis is copied in as a file
+	archive, err := genesisArchive(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if err := cli.CopyToContainer(ctx, n.id, "/home/thor", archive, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy genesis to Docker container: %w", err)
+	}
+
 	// Start the Docker container
 	if err := cli.ContainerStart(ctx, n.id, container.StartOptions{}); err != nil {
 		return fmt.Errorf("failed to start Docker container: %w", err)
@@ -148,6 +165,22 @@ func (n *Node) Start() error {
 	return nil
 }
 
+// genesisArchive returns a tar archive holding the genesis as genesis.json
+func genesisArchive(genesis []byte) (io.Reader, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "genesis.json", Mode: 0644, Size: int64(len(genesis))}); err != nil {
+		return nil, fmt.Errorf("failed to archive genesis: %w", err)
+	}
+	if _, err := tw.Write(genesis); err != nil {
+		return nil, fmt.Errorf("failed to archive genesis: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to archive genesis: %w", err)
+	}
+	return &buf, nil
+}
+
 // Stop stops the Docker container
 func (n *Node) Stop() error {
 	ctx := context.Background()
@@ -169,6 +202,34 @@ func (n *Node) Stop() error {
 	return nil
 }
 
+// Kill kills the container's processes, keeping the container and its data for Restart
+func (n *Node) Kill() error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := cli.ContainerKill(context.Background(), n.id, "SIGKILL"); err != nil {
+		return fmt.Errorf("failed to kill Docker container: %w", err)
+	}
+	return nil
+}
+
+// Restart starts the killed container again, thor resumes from the chain data in the container
+func (n *Node) Restart() error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := cli.ContainerStart(context.Background(), n.id, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to restart Docker container: %w", err)
+	}
+	return nil
+}
+
 // cleanEnodes filters out the current node's enode from the list
 func (n *Node) cleanEnodes() []string {
 	var cleanEnodes []string
@@ -201,10 +262,19 @@ func (n *Node) buildThorArgs(cleanEnodes []string) []string {
 		"--p2p-port", fmt.Sprintf("%d", n.cfg.GetP2PListenPort()),
 	)
 
+	// Listen on every interface so the metrics port can be published on the host
+	if port := n.metricsPort(); port != "" {
+		args = append(args, "--enable-metrics", "--metrics-addr", "0.0.0.0:"+port)
+	}
+
 	// Add bootnodes if any
 	if len(cleanEnodes) > 0 {
-		enodeString := strings.Join(cleanEnodes, ",")
-		args = append(args, "--bootnode", enodeString)
+		enodeString := strings.ReplaceAll(strings.Join(cleanEnodes, ","), "@"+HostGatewayName+":", "@${HOST_IP}:")
+		if n.allowedPeers {
+			args = append(args, "--allowed-peers", enodeString)
+		} else {
+			args = append(args, "--bootnode", enodeString)
+		}
 	}
 
 	// Add additional arguments
@@ -214,3 +284,44 @@ func (n *Node) buildThorArgs(cleanEnodes []string) []string {
 
 	return args
 }
+
+// metricsPort returns the port of the node's metrics address, used both in the container and on the host,
+// empty when metrics are disabled
+func (n *Node) metricsPort() string {
+	if !n.cfg.IsMetricsEnabled() {
+		return ""
+	}
+	_, port, err := net.SplitHostPort(n.cfg.GetMetricsAddr())
+	if err != nil {
+		return ""
+	}
+	return port
+}
+
+// ensureImage pulls the image unless it is available locally
+func ensureImage(ctx context.Context, cli *client.Client, imageName string) error {
+	_, err := cli.ImageInspect(ctx, imageName)
+	if err == nil {
+		return nil
+	}
+	if !client.IsErrNotFound(err) {
+		return fmt.Errorf("failed to inspect Docker image: %w", err)
+	}
+
+	out, err := cli.ImagePull(ctx, imageName, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull Docker image: %w", err)
+	}
+	defer out.Close()
+
+	// We wait for the image to be pulled
+	decoder := json.NewDecoder(out)
+	for {
+		var event map[string]interface{}
+		if err := decoder.Decode(&event); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode image pull event: %w", err)
+		}
+	}
+}
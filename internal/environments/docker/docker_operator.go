@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/vechain/networkhub/network/node"
+)
+
+// Exec runs argv inside the node's container and waits for it to finish
+func (n *Node) Exec(argv []string) (*node.ExecResult, error) {
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("command cannot be empty")
+	}
+
+	ctx := context.Background()
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	exec, err := cli.ContainerExecCreate(ctx, n.id, container.ExecOptions{
+		Cmd:          argv,
+		WorkingDir:   n.cfg.GetDataDir(),
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec in container: %w", err)
+	}
+
+	attach, err := cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer attach.Close()
+
+	// Docker multiplexes stdout and stderr on the same connection
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+		return nil, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect exec: %w", err)
+	}
+
+	return &node.ExecResult{
+		ExitCode: inspect.ExitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+	}, nil
+}
+
+// CopyFrom returns a tar archive of the file or directory at path inside the container
+func (n *Node) CopyFrom(srcPath string) (io.ReadCloser, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+
+	reader, _, err := cli.CopyFromContainer(context.Background(), n.id, n.resolvePath(srcPath))
+	if err != nil {
+		cli.Close()
+		return nil, fmt.Errorf("failed to copy from container: %w", err)
+	}
+
+	return &clientReadCloser{ReadCloser: reader, cli: cli}, nil
+}
+
+// CopyTo extracts the tar archive into the directory at path inside the container
+func (n *Node) CopyTo(dstPath string, archive io.Reader) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := cli.CopyToContainer(context.Background(), n.id, n.resolvePath(dstPath), archive, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy to container: %w", err)
+	}
+
+	return nil
+}
+
+// resolvePath resolves relative paths against the node's data directory inside the container
+func (n *Node) resolvePath(p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	return path.Join(n.cfg.GetDataDir(), p)
+}
+
+// clientReadCloser closes the Docker client together with the stream it produced
+type clientReadCloser struct {
+	io.ReadCloser
+	cli *client.Client
+}
+
+func (c *clientReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cli.Close()
+	return err
+}
+
+// CommandLine returns the thor command line the container runs
+func (n *Node) CommandLine() []string {
+	return n.thorArgs
+}
+
+// Logs returns the stdout and stderr of the node's container
+func (n *Node) Logs() (io.ReadCloser, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	reader, err := cli.ContainerLogs(context.Background(), n.id, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer reader.Close()
+
+	// Containers run without a TTY, so stdout and stderr are multiplexed
+	var logs bytes.Buffer
+	if _, err := stdcopy.StdCopy(&logs, &logs, reader); err != nil {
+		return nil, fmt.Errorf("failed to read container logs: %w", err)
+	}
+	return io.NopCloser(&logs), nil
+}
+
+// Pause freezes every process of the node's container
+func (n *Node) Pause() error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := cli.ContainerPause(context.Background(), n.id); err != nil {
+		return fmt.Errorf("failed to pause container: %w", err)
+	}
+	return nil
+}
+
+// Resume unfreezes the node's container
+func (n *Node) Resume() error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := cli.ContainerUnpause(context.Background(), n.id); err != nil {
+		return fmt.Errorf("failed to resume container: %w", err)
+	}
+	return nil
+}
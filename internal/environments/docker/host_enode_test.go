@@ -0,0 +1,39 @@
+package docker_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments/docker"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+)
+
+func TestHostEnodePorts(t *testing.T) {
+	presetNetwork := preset.LocalThreeNodesNetwork()
+	manager := docker.NewManager()
+
+	// Containers listen on the same p2p port, each behind its own host port
+	var hostPorts []string
+	for i, nodeCfg := range presetNetwork.Nodes {
+		cfg := &node.BaseNode{ID: nodeCfg.GetID(), P2PListenPort: 30303, Key: presetNetwork.Nodes[i].GetKey()}
+		enode, err := manager.HostEnode(cfg)
+		require.NoError(t, err)
+
+		u, err := url.Parse(enode)
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1", u.Hostname())
+		assert.NotEqual(t, "30303", u.Port())
+		assert.NotContains(t, hostPorts, u.Port())
+		hostPorts = append(hostPorts, u.Port())
+
+		// The port is kept for the node
+		again, err := manager.HostEnode(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, enode, again)
+	}
+
+	assert.Equal(t, docker.HostGatewayName, manager.HostIP())
+}
@@ -73,6 +73,9 @@ func TestIPAllocationInEnodes(t *testing.T) {
 	assert.True(t, strings.HasSuffix(ip1, ".2"), "First IP should end with .2")
 	assert.True(t, strings.HasSuffix(ip2, ".3"), "Second IP should end with .3")
 
+	// The gateway is the host's address on the Docker network, containers use it to reach local nodes
+	assert.True(t, strings.HasSuffix(ipManager.Gateway(), ".1"), "Gateway should end with .1")
+
 	// Generate enodes with the allocated IPs
 	enode1, err := networkCfg.Nodes[0].Enode(ip1)
 	require.NoError(t, err)
@@ -45,6 +45,11 @@ func (im *IpManager) Subnet() string {
 	return fmt.Sprintf("%s0/24", im.baseIP)
 }
 
+// Gateway returns the subnet gateway, the host's address on the Docker network
+func (im *IpManager) Gateway() string {
+	return fmt.Sprintf("%s1", im.baseIP)
+}
+
 func (im *IpManager) GetNodeIP(nodeID string) string {
 	return im.assignedIps[nodeID]
 }
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vechain/networkhub/network"
+)
+
+// containerInterface is the interface of a container attached to a single Docker network
+const containerInterface = "eth0"
+
+// ShapeLinks shapes the egress of every running container with tc netem, one netem qdisc per shaped peer.
+// Traffic to the host, such as API calls, is never shaped.
+func (m *Manager) ShapeLinks(links network.Links) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range links {
+		if _, ok := m.nodes[id]; !ok {
+			return fmt.Errorf("node %s is not running", id)
+		}
+	}
+
+	for id, dockerNode := range m.nodes {
+		peerIPs := make(map[string]network.LinkProfile, len(links[id]))
+		for peer, profile := range links[id] {
+			peerIPs[m.ipManager.GetNodeIP(peer)] = profile
+		}
+		if err := dockerNode.runNetAdmin(shapeScript(peerIPs)); err != nil {
+			return fmt.Errorf("failed to shape links of node %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// shapeScript replaces the container's qdiscs with an htb tree sending traffic for each peer IP through its own netem
+func shapeScript(peerIPs map[string]network.LinkProfile) string {
+	commands := []string{
+		fmt.Sprintf("tc qdisc del dev %s root 2>/dev/null || true", containerInterface),
+	}
+	if len(peerIPs) == 0 {
+		return strings.Join(commands, "\n")
+	}
+
+	commands = append(commands,
+		"set -e",
+		fmt.Sprintf("tc qdisc add dev %s root handle 1: htb default 1", containerInterface),
+		fmt.Sprintf("tc class add dev %s parent 1: classid 1:1 htb rate 10gbit", containerInterface),
+	)
+
+	ips := make([]string, 0, len(peerIPs))
+	for ip := range peerIPs {
+		ips = append(ips, ip)
+	}
+	slices.Sort(ips)
+
+	for i, ip := range ips {
+		class := fmt.Sprintf("%x", i+0x10)
+		commands = append(commands,
+			fmt.Sprintf("tc class add dev %s parent 1: classid 1:%s htb rate 10gbit", containerInterface, class),
+			fmt.Sprintf("tc qdisc add dev %s parent 1:%s handle %s: netem %s", containerInterface, class, class, netemArgs(peerIPs[ip])),
+			fmt.Sprintf("tc filter add dev %s protocol ip parent 1: prio 1 u32 match ip dst %s/32 flowid 1:%s", containerInterface, ip, class),
+		)
+	}
+	return strings.Join(commands, "\n")
+}
+
+// netemArgs renders a link profile as tc netem options
+func netemArgs(profile network.LinkProfile) string {
+	var args []string
+	if profile.Delay > 0 {
+		args = append(args, fmt.Sprintf("delay %dus", profile.Delay.Microseconds()))
+		if profile.Jitter > 0 {
+			args = append(args, fmt.Sprintf("%dus", profile.Jitter.Microseconds()))
+		}
+	}
+	if profile.Loss > 0 {
+		args = append(args, fmt.Sprintf("loss %g%%", profile.Loss))
+	}
+	if profile.BandwidthKbit > 0 {
+		args = append(args, fmt.Sprintf("rate %dkbit", profile.BandwidthKbit))
+	}
+	return strings.Join(args, " ")
+}
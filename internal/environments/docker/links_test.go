@@ -0,0 +1,47 @@
+package docker_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/launcher"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/preset"
+)
+
+func TestDockerLinkShaping(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.Docker
+	networkCfg.BaseID = "links-test"
+	networkCfg.ThorBuilder = nil
+	for i, node := range networkCfg.Nodes {
+		node.SetExecArtifact("vechain/thor:latest")
+		node.SetDataDir("/home/thor")
+		node.SetConfigDir("/home/thor")
+		node.SetAPIAddr(fmt.Sprintf("0.0.0.0:%d", 8745+i))
+	}
+
+	launcherEnv, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, launcherEnv.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, launcherEnv.StopNetwork())
+	})
+	require.NoError(t, networkCfg.HealthCheck(1, 2*time.Minute))
+
+	// The network keeps producing blocks with a validator on another continent
+	intercontinental, err := network.LinkPreset(network.LinkIntercontinental)
+	require.NoError(t, err)
+	require.NoError(t, launcherEnv.ShapeLinks(network.LinkShape{Node: "node3", Profile: intercontinental}))
+	require.NoError(t, networkCfg.HealthCheck(3, 2*time.Minute))
+
+	// Profiles can be changed and removed at runtime
+	crossRegion, err := network.LinkPreset(network.LinkCrossRegion)
+	require.NoError(t, err)
+	require.NoError(t, launcherEnv.ShapeLinks(network.LinkShape{Node: "node1", Peer: "node2", Profile: crossRegion}))
+	require.NoError(t, launcherEnv.ShapeLinks())
+	require.NoError(t, networkCfg.HealthCheck(5, 2*time.Minute))
+}
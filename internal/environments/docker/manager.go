@@ -4,27 +4,39 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
+	"strconv"
 	"strings"
 	"sync"
 
 	dockernetwork "github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/vechain/networkhub/internal/environments"
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
 	"github.com/vechain/networkhub/thorbuilder"
 )
 
+// HostGatewayName is the host name containers use to reach the host
+const HostGatewayName = "host.docker.internal"
+
 // Manager handles Docker container management utilities
 type Manager struct {
 	ipManager   *IpManager
 	networkName string
-	mu          sync.Mutex
+	// host ports publishing the p2p port of nodes reached by peers of other environments, by node ID
+	hostP2PPorts map[string]int
+	// running containers by node ID
+	nodes map[string]*Node
+	mu    sync.Mutex
 }
 
 // NewManager creates a new Docker container manager
 func NewManager() *Manager {
 	return &Manager{
-		ipManager: NewIPManagerRandom(),
+		ipManager:    NewIPManagerRandom(),
+		hostP2PPorts: make(map[string]int),
+		nodes:        make(map[string]*Node),
 	}
 }
 
@@ -66,21 +78,62 @@ func (m *Manager) StartNode(nodeCfg node.Config, networkCfg *network.Network, en
 	if exposedPort == nil {
 		return nil, fmt.Errorf("unable to determine API port for node %s", nodeCfg.GetID())
 	}
+	if hostPort, ok := m.hostP2PPorts[nodeCfg.GetID()]; ok {
+		exposedPort.P2PPort = strconv.Itoa(nodeCfg.GetP2PListenPort())
+		exposedPort.HostP2PPort = strconv.Itoa(hostPort)
+	}
 
 	// Create and return the docker node
 	dockerNode := NewDockerNode(nodeCfg, enodes, m.networkName, exposedPort, ipAddr)
+	dockerNode.allowedPeers = networkCfg.Topology != nil
+	dockerNode.gateway = m.ipManager.Gateway()
 	if err := dockerNode.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start docker node %s: %w", nodeCfg.GetID(), err)
 	}
+	m.nodes[nodeCfg.GetID()] = dockerNode
 
 	return dockerNode, nil
 }
 
 // StopNode stops a Docker container node
 func (m *Manager) StopNode(nodeInstance node.Lifecycle) error {
+	if dockerNode, ok := nodeInstance.(*Node); ok {
+		m.mu.Lock()
+		delete(m.nodes, dockerNode.cfg.GetID())
+		m.mu.Unlock()
+	}
 	return nodeInstance.Stop()
 }
 
+// KillNode kills a node's container, keeping it for RestartNode
+func (m *Manager) KillNode(nodeInstance node.Lifecycle) error {
+	dockerNode, ok := nodeInstance.(*Node)
+	if !ok {
+		return fmt.Errorf("not a docker node")
+	}
+
+	m.mu.Lock()
+	delete(m.nodes, dockerNode.cfg.GetID())
+	m.mu.Unlock()
+	return dockerNode.Kill()
+}
+
+// RestartNode starts the same container of a killed node again
+func (m *Manager) RestartNode(nodeInstance node.Lifecycle) error {
+	dockerNode, ok := nodeInstance.(*Node)
+	if !ok {
+		return fmt.Errorf("not a docker node")
+	}
+
+	if err := dockerNode.Restart(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.nodes[dockerNode.cfg.GetID()] = dockerNode
+	m.mu.Unlock()
+	return nil
+}
+
 // GenerateEnodes creates enode strings for all nodes using Docker IPs
 func (m *Manager) GenerateEnodes(networkCfg *network.Network) ([]string, error) {
 	var enodes []string
@@ -119,6 +172,16 @@ func (m *Manager) BuildThorBinary(thorBuilder *thorbuilder.Config) (string, erro
 	}
 
 	builder := thorbuilder.New(thorBuilder)
+
+	// Package an already built binary into a thin runtime image when requested
+	if thorBuilder.BuildConfig != nil && thorBuilder.BuildConfig.DockerFromBinary {
+		dockerImage, err := builder.BuildBinaryDockerImage()
+		if err != nil {
+			return "", fmt.Errorf("failed to package thor binary docker image: %w", err)
+		}
+		return dockerImage, nil
+	}
+
 	// For Docker, we need to build a Docker image instead of binary
 	dockerImage, err := builder.BuildDockerImage()
 	if err != nil {
@@ -134,6 +197,12 @@ func (m *Manager) ValidateNode(nodeCfg node.Config) error {
 		return fmt.Errorf("docker image cannot be empty")
 	}
 
+	if addr := nodeCfg.GetMetricsAddr(); nodeCfg.IsMetricsEnabled() && addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid metrics address %s for node %s: %w", addr, nodeCfg.GetID(), err)
+		}
+	}
+
 	// use preset dirs if not defined
 	if nodeCfg.GetConfigDir() == "" {
 		nodeCfg.SetConfigDir("/home/thor")
@@ -220,6 +289,70 @@ func (m *Manager) Cleanup() error {
 	return nil
 }
 
+// Capabilities lists the optional features of the Docker environment
+func (m *Manager) Capabilities() []environments.Capability {
+	return []environments.Capability{
+		environments.CapabilityExec,
+		environments.CapabilityPublicNetworks,
+		environments.CapabilityThorBuild,
+		environments.CapabilityMixedNetworks,
+		environments.CapabilityPartition,
+		environments.CapabilityLinkShaping,
+		environments.CapabilityPause,
+		environments.CapabilityMetrics,
+		environments.CapabilityClockSkew,
+	}
+}
+
+// HostEnode returns the enode reaching a container through its p2p port published on the host.
+// Calling it allocates a free host port for the node, published when the container starts, so
+// containers sharing a p2p port don't collide on the host.
+func (m *Manager) HostEnode(nodeCfg node.Config) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hostPort, ok := m.hostP2PPorts[nodeCfg.GetID()]
+	if !ok {
+		var err error
+		if hostPort, err = freeHostPort(); err != nil {
+			return "", fmt.Errorf("failed to allocate a host p2p port for node %s: %w", nodeCfg.GetID(), err)
+		}
+		m.hostP2PPorts[nodeCfg.GetID()] = hostPort
+	}
+
+	enode, err := nodeCfg.Enode("127.0.0.1")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(enode, fmt.Sprintf(":%d", nodeCfg.GetP2PListenPort())) + fmt.Sprintf(":%d", hostPort), nil
+}
+
+// HostIP returns host.docker.internal, which containers use to reach the host. Docker Desktop resolves it
+// in every container and containers started here map it to the host gateway elsewhere. Bootnodes are
+// resolved inside the container, falling back to the Docker network gateway.
+func (m *Manager) HostIP() string {
+	return HostGatewayName
+}
+
+// freeHostPort returns a host port free for both tcp and udp
+func freeHostPort() (int, error) {
+	for range 10 {
+		tcp, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return 0, err
+		}
+		port := tcp.Addr().(*net.TCPAddr).Port
+		udp, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", port))
+		tcp.Close()
+		if err != nil {
+			continue
+		}
+		udp.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("no port free for both tcp and udp")
+}
+
 // getExposedPort extracts port configuration from node API address
 func (m *Manager) getExposedPort(nodeCfg node.Config) *ExposedPort {
 	apiAddr := nodeCfg.GetAPIAddr()
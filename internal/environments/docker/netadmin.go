@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// NetAdminImage provides iptables and tc for the sidecars that change a node's networking.
+// Thor images ship neither, so the sidecar joins the node's network namespace instead.
+var NetAdminImage = "nicolaka/netshoot:latest"
+
+// runNetAdmin runs a shell script with NET_ADMIN in the network namespace of the node's container
+func (n *Node) runNetAdmin(script string) error {
+	ctx := context.Background()
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	if err := ensureImage(ctx, cli, NetAdminImage); err != nil {
+		return err
+	}
+
+	resp, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:      NetAdminImage,
+			Entrypoint: []string{"sh", "-c"},
+			Cmd:        []string{script},
+		},
+		&container.HostConfig{
+			NetworkMode: container.NetworkMode("container:" + n.id),
+			CapAdd:      []string{"NET_ADMIN"},
+		},
+		nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create netadmin container: %w", err)
+	}
+	defer cli.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true})
+
+	if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start netadmin container: %w", err)
+	}
+
+	statusCh, errCh := cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	var exitCode int64
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to wait for netadmin container: %w", err)
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+	if exitCode == 0 {
+		return nil
+	}
+
+	// Surface the script output to explain the failure
+	var stdout, stderr bytes.Buffer
+	if logs, err := cli.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true, ShowStderr: true}); err == nil {
+		_, _ = stdcopy.StdCopy(&stdout, &stderr, logs)
+		logs.Close()
+	}
+	return fmt.Errorf("netadmin script failed on node %s with exit code %d: %s", n.cfg.GetID(), exitCode, stderr.String())
+}
@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partitionChain is the iptables chain holding the partition rules of a node
+const partitionChain = "NETWORKHUB-PARTITION"
+
+// healScript removes every partition rule of a node
+var healScript = fmt.Sprintf("iptables -F %s 2>/dev/null || true", partitionChain)
+
+// Partition drops all traffic between containers of different groups with iptables rules
+// applied inside each container's network namespace
+func (m *Manager) Partition(groups ...[]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, group := range groups {
+		for _, id := range group {
+			dockerNode, ok := m.nodes[id]
+			if !ok {
+				return fmt.Errorf("node %s is not running", id)
+			}
+
+			var blocked []string
+			for j, other := range groups {
+				if i == j {
+					continue
+				}
+				for _, otherID := range other {
+					blocked = append(blocked, m.ipManager.GetNodeIP(otherID))
+				}
+			}
+
+			if err := dockerNode.runNetAdmin(partitionScript(blocked)); err != nil {
+				return fmt.Errorf("failed to partition node %s: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Heal removes the partition rules from every running container
+func (m *Manager) Heal() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, dockerNode := range m.nodes {
+		if err := dockerNode.runNetAdmin(healScript); err != nil {
+			return fmt.Errorf("failed to heal node %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// partitionScript replaces the node's partition rules with rules dropping traffic to and from the blocked IPs
+func partitionScript(blocked []string) string {
+	commands := []string{
+		"set -e",
+		fmt.Sprintf("iptables -N %s 2>/dev/null || iptables -F %s", partitionChain, partitionChain),
+		fmt.Sprintf("iptables -C INPUT -j %s 2>/dev/null || iptables -I INPUT -j %s", partitionChain, partitionChain),
+		fmt.Sprintf("iptables -C OUTPUT -j %s 2>/dev/null || iptables -I OUTPUT -j %s", partitionChain, partitionChain),
+	}
+	for _, ip := range blocked {
+		commands = append(commands,
+			fmt.Sprintf("iptables -A %s -s %s -j DROP", partitionChain, ip),
+			fmt.Sprintf("iptables -A %s -d %s -j DROP", partitionChain, ip),
+		)
+	}
+	return strings.Join(commands, "\n")
+}
@@ -0,0 +1,54 @@
+package docker_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/launcher"
+	"github.com/vechain/networkhub/preset"
+	"github.com/vechain/thor/v2/thorclient"
+)
+
+func TestDockerPartition(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.Docker
+	networkCfg.BaseID = "partition-test"
+	networkCfg.ThorBuilder = nil
+	for i, node := range networkCfg.Nodes {
+		node.SetExecArtifact("vechain/thor:latest")
+		node.SetDataDir("/home/thor")
+		node.SetConfigDir("/home/thor")
+		node.SetAPIAddr(fmt.Sprintf("0.0.0.0:%d", 8645+i))
+	}
+
+	launcherEnv, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, launcherEnv.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, launcherEnv.StopNetwork())
+	})
+	require.NoError(t, networkCfg.HealthCheck(1, 2*time.Minute))
+
+	majority := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr())
+	minority := thorclient.New(networkCfg.Nodes[2].GetHTTPAddr())
+
+	// Each side keeps producing its own chain while partitioned
+	require.NoError(t, launcherEnv.Partition([]string{"node1", "node2"}, []string{"node3"}))
+	best, err := majority.Block("best")
+	require.NoError(t, err)
+	forked := best.Number + 3
+	require.NoError(t, networkCfg.HealthCheck(forked, 2*time.Minute))
+
+	majorityBlk, err := majority.Block(fmt.Sprint(forked))
+	require.NoError(t, err)
+	minorityBlk, err := minority.Block(fmt.Sprint(forked))
+	require.NoError(t, err)
+	require.NotEqual(t, majorityBlk.ID, minorityBlk.ID)
+
+	// Healing the partition lets the network converge on one chain again
+	require.NoError(t, launcherEnv.Heal())
+	require.NoError(t, networkCfg.HealthCheck(forked+3, 2*time.Minute))
+}
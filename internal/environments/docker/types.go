@@ -4,4 +4,7 @@ package docker
 type ExposedPort struct {
 	HostPort      string
 	ContainerPort string
+	// P2PPort is published on the host as HostP2PPort for tcp and udp when set
+	P2PPort     string
+	HostP2PPort string
 }
@@ -0,0 +1,169 @@
+package fake
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// handler serves the subset of the thor REST API used by networkhub
+func (n *Node) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /blocks/{revision}", n.handleBlock)
+	mux.HandleFunc("GET /node/network/peers", n.handlePeers)
+	mux.HandleFunc("GET /accounts/{address}", n.handleAccount)
+	mux.HandleFunc("GET /transactions/{id}/receipt", n.handleReceipt)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.mu.RLock()
+		offline := n.offline
+		n.mu.RUnlock()
+
+		if offline {
+			http.Error(w, "node is offline", http.StatusServiceUnavailable)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// handleBlock serves GET /blocks/{revision}, answering null for unknown blocks like thor does
+func (n *Node) handleBlock(w http.ResponseWriter, r *http.Request) {
+	block, err := n.block(r.PathValue("revision"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, block)
+}
+
+// handlePeers serves GET /node/network/peers
+func (n *Node) handlePeers(w http.ResponseWriter, _ *http.Request) {
+	stats := []*api.PeerStats{}
+	for _, peer := range n.peerNodes() {
+		best := peer.Best()
+		stats = append(stats, &api.PeerStats{
+			Name:        "thor/fake",
+			BestBlockID: best.ID,
+			TotalScore:  best.TotalScore,
+			PeerID:      peer.nodeCfg.PeerID(),
+			NetAddr:     peer.nodeCfg.GetAPIAddr(),
+		})
+	}
+	writeJSON(w, stats)
+}
+
+// handleAccount serves GET /accounts/{address}, unknown accounts are empty
+func (n *Node) handleAccount(w http.ResponseWriter, r *http.Request) {
+	addr, err := thor.ParseAddress(r.PathValue("address"))
+	if err != nil {
+		http.Error(w, "address: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.RLock()
+	account, ok := n.accounts[addr]
+	n.mu.RUnlock()
+
+	if !ok {
+		account = &api.Account{
+			Balance: &math.HexOrDecimal256{},
+			Energy:  &math.HexOrDecimal256{},
+		}
+	}
+	writeJSON(w, account)
+}
+
+// handleReceipt serves GET /transactions/{id}/receipt, answering null for unknown transactions like thor does
+func (n *Node) handleReceipt(w http.ResponseWriter, r *http.Request) {
+	id, err := thor.ParseBytes32(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.RLock()
+	receipt := n.receipts[id]
+	n.mu.RUnlock()
+
+	writeJSON(w, receipt)
+}
+
+// serveMetrics serves GET /metrics on the node's metrics address with the best block number
+func (n *Node) serveMetrics() error {
+	addr := n.nodeCfg.GetMetricsAddr()
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprintf(w, "# TYPE thor_fake_best_block gauge\nthor_fake_best_block %d\n", n.Best().Number)
+	})
+	n.metricsServer = &httptest.Server{
+		Listener: listener,
+		Config:   &http.Server{Handler: mux},
+	}
+	n.metricsServer.Start()
+	n.nodeCfg.SetMetricsAddr(listener.Addr().String())
+	return nil
+}
+
+// block resolves a revision (best, justified, finalized, number or ID) to a block, nil if not found
+func (n *Node) block(revision string) (*api.JSONCollapsedBlock, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var number uint32
+	switch {
+	case revision == "" || revision == "best":
+		number = uint32(len(n.blocks) - 1)
+	case revision == "justified" || revision == "finalized":
+		number = n.finalized
+	case strings.HasPrefix(revision, "0x") && len(revision) == 66:
+		id, err := thor.ParseBytes32(revision)
+		if err != nil {
+			return nil, err
+		}
+		number = binary.BigEndian.Uint32(id[:4])
+		if int(number) >= len(n.blocks) || n.blocks[number].ID != id {
+			return nil, nil
+		}
+	default:
+		parsed, err := strconv.ParseUint(revision, 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		number = uint32(parsed)
+	}
+
+	if int(number) >= len(n.blocks) {
+		return nil, nil
+	}
+
+	block := *n.blocks[number]
+	summary := *block.JSONBlockSummary
+	summary.IsTrunk = true
+	summary.IsFinalized = number <= n.finalized
+	block.JSONBlockSummary = &summary
+	return &block, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
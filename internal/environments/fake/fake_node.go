@@ -0,0 +1,387 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// defaultBlockInterval is used for block timestamps when the genesis does not configure one
+const defaultBlockInterval = 10
+
+// Node is a fake thor node whose chain, peers and accounts are scripted by the caller
+type Node struct {
+	nodeCfg    node.Config
+	networkCfg *network.Network
+	lookup     func(id string) *Node
+
+	mu        sync.RWMutex
+	blocks    []*api.JSONCollapsedBlock
+	finalized uint32
+	peers     []string
+	accounts  map[thor.Address]*api.Account
+	receipts  map[thor.Bytes32]*api.Receipt
+	offline   bool
+
+	server        *httptest.Server
+	metricsServer *httptest.Server
+	stopRun       chan struct{}
+
+	logMu sync.Mutex
+	logs  bytes.Buffer
+}
+
+// NewFakeNode creates a fake node, lookup resolves peer IDs to running fake nodes
+func NewFakeNode(nodeCfg node.Config, networkCfg *network.Network, lookup func(id string) *Node) *Node {
+	return &Node{
+		nodeCfg:    nodeCfg,
+		networkCfg: networkCfg,
+		lookup:     lookup,
+		accounts:   make(map[thor.Address]*api.Account),
+		receipts:   make(map[thor.Bytes32]*api.Receipt),
+	}
+}
+
+// Start seeds the chain with a genesis block and starts serving the API.
+// An empty API address listens on an ephemeral loopback port and updates the node config.
+func (n *Node) Start() error {
+	if err := n.seed(); err != nil {
+		return fmt.Errorf("failed to seed fake chain: %w", err)
+	}
+
+	addr := n.nodeCfg.GetAPIAddr()
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	n.server = &httptest.Server{
+		Listener: listener,
+		Config:   &http.Server{Handler: n.handler()},
+	}
+	n.server.Start()
+	n.nodeCfg.SetAPIAddr(listener.Addr().String())
+
+	if n.nodeCfg.IsMetricsEnabled() {
+		if err := n.serveMetrics(); err != nil {
+			n.server.Close()
+			return err
+		}
+	}
+
+	n.logMu.Lock()
+	n.logs.Reset()
+	n.logMu.Unlock()
+	n.logf("started, api %s", n.nodeCfg.GetAPIAddr())
+
+	slog.Info("started fake node", "id", n.nodeCfg.GetID(), "api", n.nodeCfg.GetAPIAddr())
+	return nil
+}
+
+// Stop stops block production and the API server
+func (n *Node) Stop() error {
+	n.AutoProduce(0)
+	if n.server != nil {
+		n.server.Close()
+	}
+	if n.metricsServer != nil {
+		n.metricsServer.Close()
+	}
+	n.logf("stopped")
+	slog.Info("fake node stopped", "id", n.nodeCfg.GetID())
+	return nil
+}
+
+// ProduceBlocks appends count blocks on top of the best block.
+// Block IDs are derived from the parent ID, so nodes sharing a history produce identical blocks.
+func (n *Node) ProduceBlocks(count int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.produce("", count)
+	n.logf("produced %d blocks, best block %d", count, len(n.blocks)-1)
+}
+
+// Fork rewinds the chain to the given block number and appends count blocks on the named branch.
+// Nodes forking on different branches from the same block diverge from that height on.
+func (n *Node) Fork(number uint32, branch string, count int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if int(number) >= len(n.blocks) {
+		return fmt.Errorf("block %d is beyond best block %d", number, len(n.blocks)-1)
+	}
+	n.blocks = n.blocks[:number+1]
+	n.finalized = min(n.finalized, number)
+	n.produce(branch, count)
+	n.logf("forked at block %d on branch %q, best block %d", number, branch, len(n.blocks)-1)
+	return nil
+}
+
+// SyncFrom replaces the chain with a copy of the other node's chain
+func (n *Node) SyncFrom(other *Node) {
+	other.mu.RLock()
+	blocks := append([]*api.JSONCollapsedBlock(nil), other.blocks...)
+	finalized := other.finalized
+	other.mu.RUnlock()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.blocks = blocks
+	n.finalized = finalized
+	n.logf("synced from node %s, best block %d", other.nodeCfg.GetID(), len(n.blocks)-1)
+}
+
+// Finalize marks every block up to number as finalized
+func (n *Node) Finalize(number uint32) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if int(number) >= len(n.blocks) {
+		return fmt.Errorf("block %d is beyond best block %d", number, len(n.blocks)-1)
+	}
+	n.finalized = number
+	n.logf("finalized block %d", number)
+	return nil
+}
+
+// AutoProduce appends a block every interval until the node stops, zero stops production
+func (n *Node) AutoProduce(interval time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.stopRun != nil {
+		close(n.stopRun)
+		n.stopRun = nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	n.stopRun = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				n.ProduceBlocks(1)
+			}
+		}
+	}()
+}
+
+// Best returns the best block
+func (n *Node) Best() *api.JSONCollapsedBlock {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.blocks[len(n.blocks)-1]
+}
+
+// SetPeers overrides the peers reported by the node with the given node IDs.
+// By default a fake node reports the running nodes its network topology expects, every other node without one.
+func (n *Node) SetPeers(ids ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.peers = append([]string{}, ids...)
+}
+
+// SetAccount overrides the state reported for an account
+func (n *Node) SetAccount(addr thor.Address, account *api.Account) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.accounts[addr] = account
+}
+
+// SetReceipt makes the node report the transaction as included with the given receipt
+func (n *Node) SetReceipt(id thor.Bytes32, receipt *api.Receipt) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.receipts[id] = receipt
+}
+
+// SetOffline makes every API request fail with 503 Service Unavailable
+func (n *Node) SetOffline(offline bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.offline = offline
+	n.logf("offline: %t", offline)
+}
+
+// Pause takes the node offline like a frozen process
+func (n *Node) Pause() error {
+	n.SetOffline(true)
+	return nil
+}
+
+// Resume brings a paused node back online
+func (n *Node) Resume() error {
+	n.SetOffline(false)
+	return nil
+}
+
+// CommandLine returns a thor-like command line describing the fake node
+func (n *Node) CommandLine() []string {
+	return []string{"fake-thor", "--api-addr", n.nodeCfg.GetAPIAddr()}
+}
+
+// Logs returns the scripted events of the node since it last started
+func (n *Node) Logs() (io.ReadCloser, error) {
+	n.logMu.Lock()
+	defer n.logMu.Unlock()
+	return io.NopCloser(bytes.NewReader(bytes.Clone(n.logs.Bytes()))), nil
+}
+
+// logf appends a timestamped line to the node's logs
+func (n *Node) logf(format string, args ...any) {
+	n.logMu.Lock()
+	defer n.logMu.Unlock()
+	fmt.Fprintf(&n.logs, "%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+// seed resets the chain to the genesis block and loads the genesis accounts
+func (n *Node) seed() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var (
+		genesisID  thor.Bytes32
+		launchTime uint64
+		gasLimit   uint64
+	)
+	if gen := n.nodeCfg.GetGenesis(); gen != nil {
+		genesisBytes, err := nodegenesis.Marshal(gen)
+		if err != nil {
+			return fmt.Errorf("unable to marshal genesis: %w", err)
+		}
+		genesisID = thor.Blake2b(genesisBytes)
+		if gen.CustomGenesis != nil {
+			launchTime = gen.LaunchTime
+			gasLimit = gen.GasLimit
+			for _, acc := range gen.Accounts {
+				n.accounts[acc.Address] = &api.Account{
+					Balance: (*math.HexOrDecimal256)(acc.Balance),
+					Energy:  (*math.HexOrDecimal256)(acc.Energy),
+					HasCode: acc.Code != "",
+				}
+			}
+		}
+	} else {
+		genesisID = thor.Blake2b([]byte(n.networkCfg.BaseID))
+	}
+	binary.BigEndian.PutUint32(genesisID[:], 0)
+
+	n.blocks = []*api.JSONCollapsedBlock{{
+		JSONBlockSummary: &api.JSONBlockSummary{
+			ID:        genesisID,
+			Timestamp: launchTime,
+			GasLimit:  gasLimit,
+		},
+		Transactions: []thor.Bytes32{},
+	}}
+	n.finalized = 0
+	return nil
+}
+
+// produce appends count blocks on the given branch, the caller must hold the lock
+func (n *Node) produce(branch string, count int) {
+	signer := n.signer()
+	for range count {
+		parent := n.blocks[len(n.blocks)-1]
+		number := parent.Number + 1
+
+		id := thor.Blake2b(parent.ID.Bytes(), []byte(branch))
+		binary.BigEndian.PutUint32(id[:], number)
+
+		n.blocks = append(n.blocks, &api.JSONCollapsedBlock{
+			JSONBlockSummary: &api.JSONBlockSummary{
+				Number:      number,
+				ID:          id,
+				ParentID:    parent.ID,
+				Timestamp:   n.timestamp(number),
+				GasLimit:    parent.GasLimit,
+				Beneficiary: signer,
+				TotalScore:  parent.TotalScore + 1,
+				Signer:      signer,
+			},
+			Transactions: []thor.Bytes32{},
+		})
+	}
+}
+
+// timestamp returns the time the node stamps on the block of the given number: its slot time
+// as seen through the node's clock skew, if any
+func (n *Node) timestamp(number uint32) uint64 {
+	launchTime := n.blocks[0].Timestamp
+	elapsed := uint64(number) * n.blockInterval()
+
+	skew := n.nodeCfg.GetClockSkew()
+	if skew == nil {
+		return launchTime + elapsed
+	}
+	skewed := float64(launchTime+elapsed) + skew.Offset.Seconds() + skew.Drift*float64(elapsed)
+	return uint64(max(skewed, 0))
+}
+
+// blockInterval returns the block interval configured in the genesis
+func (n *Node) blockInterval() uint64 {
+	if gen := n.nodeCfg.GetGenesis(); gen != nil && gen.Config != nil && gen.Config.BlockInterval > 0 {
+		return gen.Config.BlockInterval
+	}
+	return defaultBlockInterval
+}
+
+// signer returns the master address derived from the node key, if any
+func (n *Node) signer() thor.Address {
+	privKey, err := crypto.HexToECDSA(n.nodeCfg.GetKey())
+	if err != nil {
+		return thor.Address{}
+	}
+	return thor.Address(crypto.PubkeyToAddress(privKey.PublicKey))
+}
+
+// peerNodes returns the running nodes currently reported as peers
+func (n *Node) peerNodes() []*Node {
+	n.mu.RLock()
+	ids := n.peers
+	n.mu.RUnlock()
+
+	if ids == nil {
+		expectedPeers, err := n.networkCfg.ExpectedPeers()
+		if err != nil {
+			return nil
+		}
+		ids = expectedPeers[n.nodeCfg.GetID()]
+	}
+
+	var peers []*Node
+	if n.lookup == nil {
+		return peers
+	}
+	for _, id := range ids {
+		if peer := n.lookup(id); peer != nil {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
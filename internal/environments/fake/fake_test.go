@@ -0,0 +1,610 @@
+package fake_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/client"
+	"github.com/vechain/networkhub/genesisbuilder"
+	"github.com/vechain/networkhub/internal/environments/fake"
+	"github.com/vechain/networkhub/internal/environments/fake/faketest"
+	"github.com/vechain/networkhub/internal/environments/launcher"
+	"github.com/vechain/networkhub/metrics"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/httpclient"
+)
+
+// startFakeNetwork launches the three node preset on fake nodes listening on ephemeral ports
+func startFakeNetwork(t *testing.T) (*network.Network, map[string]*fake.Node) {
+	return launchFakeNetwork(t, preset.LocalThreeNodesNetwork())
+}
+
+// launchFakeNetwork launches the network on fake nodes listening on ephemeral ports
+func launchFakeNetwork(t *testing.T, networkCfg *network.Network) (*network.Network, map[string]*fake.Node) {
+	faketest.UseFake(networkCfg)
+
+	env, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, env.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, env.StopNetwork())
+	})
+
+	nodes := make(map[string]*fake.Node)
+	for id, instance := range env.Nodes() {
+		nodes[id] = instance.(*fake.Node)
+	}
+	return networkCfg, nodes
+}
+
+func TestFakeNetworkHealthCheck(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	for _, n := range nodes {
+		n.ProduceBlocks(3)
+	}
+
+	start := time.Now()
+	require.NoError(t, networkCfg.HealthCheck(3, time.Second))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestFakeValidatorsNetwork(t *testing.T) {
+	networkCfg, err := preset.LocalValidatorsNetwork("fake validators", 20)
+	require.NoError(t, err)
+	networkCfg, nodes := launchFakeNetwork(t, networkCfg)
+	require.Len(t, nodes, 20)
+
+	// Fake nodes sign with the node key, which is the staked master
+	gen := networkCfg.Nodes[0].GetGenesis()
+	for _, n := range nodes {
+		n.ProduceBlocks(1)
+	}
+	require.NoError(t, networkCfg.HealthCheck(1, time.Second))
+	assert.Equal(t, gen.Stakers[19].Master, nodes["node20"].Best().Signer)
+}
+
+func TestFakeNetworkFork(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	for _, n := range nodes {
+		n.ProduceBlocks(3)
+	}
+	require.NoError(t, nodes["node3"].Fork(1, "minority", 2))
+
+	require.NoError(t, networkCfg.HealthCheck(1, 100*time.Millisecond))
+	require.ErrorContains(t, networkCfg.HealthCheck(2, 100*time.Millisecond), "block hash mismatch at height 2")
+
+	// Syncing back to the majority chain resolves the fork
+	nodes["node3"].SyncFrom(nodes["node1"])
+	require.NoError(t, networkCfg.HealthCheck(3, 100*time.Millisecond))
+}
+
+func TestFakeForkDetection(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	nodes["node1"].ProduceBlocks(2)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	nodes["node3"].SyncFrom(nodes["node1"])
+
+	report, err := networkCfg.DetectForks()
+	require.NoError(t, err)
+	assert.False(t, report.Forked)
+	assert.Equal(t, uint32(2), report.Ancestor.Number)
+	assert.Empty(t, report.Branches)
+
+	// node3 builds its own branch on block 1 while the others extend block 2
+	nodes["node1"].ProduceBlocks(2)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	require.NoError(t, nodes["node3"].Fork(1, "minority", 4))
+
+	fork, err := networkCfg.DetectForks()
+	require.NoError(t, err)
+	require.True(t, fork.Forked)
+	assert.Equal(t, uint32(1), fork.Ancestor.Number)
+	require.Len(t, fork.Branches, 2)
+
+	majority, minority := fork.Branch("node1"), fork.Branch("node3")
+	assert.Equal(t, []string{"node1", "node2"}, majority.Nodes)
+	assert.Equal(t, uint32(3), majority.Length)
+	assert.Equal(t, []string{"node3"}, minority.Nodes)
+	assert.Equal(t, uint32(4), minority.Length)
+	require.Len(t, minority.Blocks, 4)
+	assert.Equal(t, fork.Ancestor.ID, minority.Blocks[0].ParentID)
+	assert.Equal(t, uint32(2), minority.Blocks[0].Number)
+	assert.NotEqual(t, majority.Blocks[0].ID, minority.Blocks[0].ID)
+	assert.NotZero(t, minority.Blocks[0].Timestamp)
+	assert.NotEqual(t, majority.Blocks[0].Signer, minority.Blocks[0].Signer)
+
+	winner, err := networkCfg.ForkResolution(fork)
+	require.NoError(t, err)
+	assert.Nil(t, winner)
+
+	// node3 reorganises onto the majority branch
+	nodes["node3"].SyncFrom(nodes["node1"])
+	winner, err = networkCfg.ForkResolution(fork)
+	require.NoError(t, err)
+	require.NotNil(t, winner)
+	assert.Equal(t, majority, winner)
+}
+
+func TestFakeForkDetectionLaggingNode(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	// node3 stops at block 2 while node1 and node2 agree up to block 4 and then split
+	nodes["node1"].ProduceBlocks(2)
+	nodes["node3"].SyncFrom(nodes["node1"])
+	nodes["node1"].ProduceBlocks(4)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	require.NoError(t, nodes["node2"].Fork(4, "split", 3))
+
+	fork, err := networkCfg.DetectForks()
+	require.NoError(t, err)
+	require.True(t, fork.Forked)
+	assert.Equal(t, uint32(4), fork.Ancestor.Number)
+	require.Len(t, fork.Branches, 2)
+	assert.Equal(t, []string{"node1"}, fork.Branch("node1").Nodes)
+	assert.Equal(t, []string{"node2"}, fork.Branch("node2").Nodes)
+	assert.Nil(t, fork.Branch("node3"), "node3 is behind, not forked")
+
+	// node2 reorganises onto node1's branch, node3 still lags
+	nodes["node2"].SyncFrom(nodes["node1"])
+	winner, err := networkCfg.ForkResolution(fork)
+	require.NoError(t, err)
+	require.NotNil(t, winner)
+	assert.Equal(t, []string{"node1"}, winner.Nodes)
+}
+
+func TestFakeWaitHelpers(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	for _, n := range nodes {
+		n.ProduceBlocks(3)
+	}
+	nodes["node1"].ProduceBlocks(2)
+	nodes["node1"].Finalize(2)
+	nodes["node2"].SyncFrom(nodes["node1"])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, networkCfg.WaitForBlock(ctx, 3))
+
+	// The timeout names the node that did not reach the target
+	short, cancelShort := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelShort()
+	err := networkCfg.WaitForBlock(short, 5)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "node node3: best block 3 is below 5")
+	require.NotContains(t, err.Error(), "node node1")
+
+	// node3 catches up while waiting
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		nodes["node3"].SyncFrom(nodes["node1"])
+	}()
+	require.NoError(t, networkCfg.WaitForFinalized(ctx, 2))
+
+	txID := thor.Bytes32{1}
+	for _, n := range nodes {
+		n.SetReceipt(txID, &api.Receipt{GasUsed: 21000, Reverted: true})
+	}
+	receipt, err := networkCfg.WaitForTxReceipt(ctx, txID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21000), receipt.GasUsed)
+	assert.True(t, receipt.Reverted)
+
+	short, cancelShort = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelShort()
+	_, err = networkCfg.WaitForTxReceipt(short, thor.Bytes32{2})
+	require.ErrorContains(t, err, "not included yet")
+
+	err = networkCfg.WaitForAllNodes(ctx, func(nodeCfg node.Config, client *thorclient.Client) error {
+		peers, err := client.Peers()
+		if err != nil {
+			return err
+		}
+		if len(peers) != 2 {
+			return fmt.Errorf("node %s has %d peers", nodeCfg.GetID(), len(peers))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestFakeNetworkPeers(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	peers, err := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr()).Peers()
+	require.NoError(t, err)
+	assert.Len(t, peers, 2)
+
+	nodes["node1"].SetPeers("node2")
+	require.ErrorContains(t, networkCfg.HealthCheck(0, 100*time.Millisecond), "node node1 has peers [node2], expected [node2 node3]")
+}
+
+func TestFakeHealthReport(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	nodes["node1"].ProduceBlocks(3)
+	nodes["node1"].Finalize(1)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	nodes["node3"].SetOffline(true)
+
+	report, err := networkCfg.HealthReport(3, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Nodes, 3)
+
+	// Every node is reported, not only the first failing one
+	node1 := report.Node("node1")
+	require.NotNil(t, node1)
+	assert.True(t, node1.Reachable)
+	assert.Equal(t, uint32(3), node1.Best.Number)
+	assert.Equal(t, uint32(1), node1.Finalized.Number)
+	assert.Equal(t, node1.Best.ID, node1.Block.ID)
+	assert.Equal(t, 2, node1.PeerCount)
+	assert.Equal(t, []string{"node2", "node3"}, node1.Peers)
+	assert.Equal(t, node1.Block, report.Node("node2").Block)
+
+	node3 := report.Node("node3")
+	assert.False(t, node3.Reachable)
+	assert.NotEmpty(t, node3.Error)
+	assert.Nil(t, node3.Best)
+	assert.Len(t, report.Problems, 1)
+	require.ErrorContains(t, report.Err(), "node node3 is unreachable")
+
+	// The report is stored as a CI artefact
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded network.HealthReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.Nodes, decoded.Nodes)
+
+	nodes["node3"].SetOffline(false)
+	nodes["node3"].SyncFrom(nodes["node1"])
+	report, err = networkCfg.HealthReport(3, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy)
+	assert.NoError(t, report.Err())
+}
+
+func TestFakeNetworkTopology(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		topology *network.Topology
+		peers    map[string][]string
+	}{
+		{
+			name:     "Line",
+			topology: &network.Topology{Shape: network.TopologyLine},
+			peers:    map[string][]string{"node1": {"node2"}, "node2": {"node1", "node3"}, "node4": {"node3"}},
+		},
+		{
+			name:     "Star",
+			topology: &network.Topology{Shape: network.TopologyStar, Hubs: []string{"node2"}},
+			peers:    map[string][]string{"node1": {"node2"}, "node2": {"node1", "node3", "node4"}},
+		},
+		{
+			name:     "IsolatedObserver",
+			topology: &network.Topology{Isolated: []string{"node4"}},
+			peers:    map[string][]string{"node1": {"node2", "node3"}, "node4": {}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			networkCfg := preset.LocalFourNodesHayabusa()
+			for i, nodeCfg := range networkCfg.Nodes {
+				nodeCfg.SetID(fmt.Sprintf("node%d", i+1))
+			}
+			networkCfg.Topology = tc.topology
+			networkCfg, nodes := launchFakeNetwork(t, networkCfg)
+
+			require.NoError(t, networkCfg.HealthCheck(0, 100*time.Millisecond))
+			for _, nodeCfg := range networkCfg.Nodes {
+				expected, ok := tc.peers[nodeCfg.GetID()]
+				if !ok {
+					continue
+				}
+				peers, err := thorclient.New(nodeCfg.GetHTTPAddr()).Peers()
+				require.NoError(t, err)
+				assert.Len(t, peers, len(expected), nodeCfg.GetID())
+			}
+
+			// A node with an unexpected peer set fails the check
+			nodes["node1"].SetPeers("node2", "node3", "node4")
+			require.ErrorContains(t, networkCfg.HealthCheck(0, 100*time.Millisecond), "node node1 has peers [node2 node3 node4]")
+		})
+	}
+}
+
+func TestFakeClientPartition(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	c, nodes := faketest.StartClient(t, networkCfg)
+	require.True(t, c.Supports(client.CapabilityPartition))
+
+	require.ErrorContains(t, c.Partition([]string{"node1", "node2"}), "at least 2 groups")
+	require.ErrorContains(t, c.Partition([]string{"node1"}, []string{"node2"}), "node node3 is not in any partition group")
+	require.NoError(t, c.Partition([]string{"node1", "node2"}, []string{"node3"}))
+
+	// Each side extends its own chain, HealthCheck only compares nodes within a group
+	node1, node2, node3 := nodes["node1"], nodes["node2"], nodes["node3"]
+	node1.ProduceBlocks(3)
+	node2.SyncFrom(node1)
+	require.NoError(t, node3.Fork(0, "minority", 3))
+	require.NoError(t, networkCfg.HealthCheck(3, 100*time.Millisecond))
+
+	peers, err := thorclient.New(networkCfg.Nodes[2].GetHTTPAddr()).Peers()
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+
+	// Once healed the fork shows up until the minority syncs
+	require.NoError(t, c.Heal())
+	require.ErrorContains(t, networkCfg.HealthCheck(3, 100*time.Millisecond), "block hash mismatch at height 3")
+	node3.SyncFrom(node1)
+	require.NoError(t, networkCfg.HealthCheck(3, 100*time.Millisecond))
+}
+
+func TestFakeClientMetrics(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Nodes[0].SetMetricsEnabled(true)
+	networkCfg.Nodes[1].SetMetricsEnabled(true)
+	c, _ := faketest.StartClient(t, networkCfg)
+	require.True(t, c.Supports(client.CapabilityMetrics))
+
+	// Metrics ports are allocated at start
+	endpoints, err := c.MetricsEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "http://"+networkCfg.Nodes[0].GetMetricsAddr()+"/metrics", endpoints["node1"])
+	assert.NotContains(t, endpoints, "node3")
+	assert.Contains(t, httpGet(t, endpoints["node1"]), "thor_fake_best_block 0")
+
+	hub, err := metrics.Serve("127.0.0.1:0")
+	require.NoError(t, err)
+	defer hub.Close()
+
+	require.NoError(t, networkCfg.HealthCheck(0, 100*time.Millisecond))
+	require.NoError(t, c.StopNode("node3"))
+	require.NoError(t, c.StartNode("node3"))
+
+	exposition := httpGet(t, "http://"+hub.Addr+"/metrics")
+	assert.Contains(t, exposition, `networkhub_block_height{node="node1"} 0`)
+	assert.Contains(t, exposition, `networkhub_node_restarts_total{node="node3"} 1`)
+	assert.Contains(t, exposition, `networkhub_health_check_duration_seconds_count{healthy="true"}`)
+
+	path := filepath.Join(t.TempDir(), "prometheus.yml")
+	require.NoError(t, c.WriteScrapeConfig(path, hub.Addr))
+	scrapeConfig, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`scrape_configs:
+  - job_name: networkhub
+    static_configs:
+      - targets: [%q]
+  - job_name: thor
+    static_configs:
+      - targets: [%q]
+        labels:
+          node: "node1"
+      - targets: [%q]
+        labels:
+          node: "node2"
+`, hub.Addr, networkCfg.Nodes[0].GetMetricsAddr(), networkCfg.Nodes[1].GetMetricsAddr()), string(scrapeConfig))
+}
+
+// httpGet returns the body of a successful GET request
+func httpGet(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestFakeClientDiagnostics(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	c, nodes := faketest.StartClient(t, networkCfg)
+	nodes["node3"].ProduceBlocks(2)
+	require.Error(t, networkCfg.HealthCheck(2, 100*time.Millisecond))
+
+	path := filepath.Join(t.TempDir(), "diagnostics.tar.gz")
+	require.NoError(t, c.CollectDiagnostics(path))
+	files := readTarball(t, path)
+
+	assert.NotContains(t, files["network.json"], networkCfg.Nodes[0].GetKey())
+	assert.Contains(t, files["network.json"], `"key": "REDACTED"`)
+
+	var report network.HealthReport
+	require.NoError(t, json.Unmarshal([]byte(files["health.json"]), &report))
+	assert.False(t, report.Healthy)
+	assert.Equal(t, uint32(2), report.Block)
+
+	assert.Contains(t, files["nodes/node1/genesis.json"], `"launchTime"`)
+	assert.Equal(t, "fake-thor --api-addr "+networkCfg.Nodes[0].GetAPIAddr()+"\n", files["nodes/node1/command.txt"])
+	assert.Contains(t, files["nodes/node3/thor.log"], "produced 2 blocks, best block 2")
+
+	var peers []*api.PeerStats
+	require.NoError(t, json.Unmarshal([]byte(files["nodes/node1/peers.json"]), &peers))
+	assert.Len(t, peers, 2)
+
+	// The fake environment runs no thor binary, so versions and data dirs are reported missing
+	assert.NotContains(t, files, "nodes/node1/version.txt")
+	assert.Contains(t, files["errors.txt"], "node node1 version: fake environment does not support exec and file copy")
+
+	require.NoError(t, c.CollectDiagnostics(path, client.WithDataDirs()))
+	assert.Contains(t, readTarball(t, path)["errors.txt"], "node node1 data dir:")
+}
+
+// readTarball returns the content of every file of a gzipped tarball by name
+func readTarball(t *testing.T, path string) map[string]string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestFakeClientExportSnapshot(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	c, nodes := faketest.StartClient(t, networkCfg)
+	addr := thor.BytesToAddress([]byte("scripted"))
+	nodes["node1"].SetAccount(addr, &api.Account{
+		Balance: (*math.HexOrDecimal256)(new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17))),
+		Energy:  (*math.HexOrDecimal256)(big.NewInt(7)),
+	})
+
+	// The genesis accounts by default
+	path := filepath.Join(t.TempDir(), "snapshot.csv")
+	require.NoError(t, c.ExportSnapshot(path, "best"))
+	accounts, err := genesisbuilder.LoadSnapshot(path)
+	require.NoError(t, err)
+	gen := networkCfg.Nodes[0].GetGenesis()
+	require.Len(t, accounts, len(gen.Accounts))
+	assert.Equal(t, (*big.Int)(gen.Accounts[0].Balance), (*big.Int)(accounts[0].Balance))
+
+	path = filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, c.ExportSnapshot(path, "0", addr))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"vet": "1.5"`)
+	assert.Contains(t, string(data), `"vtho": "0.000000000000000007"`)
+}
+
+func TestFakeClockSkew(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Nodes[2].SetClockSkew(&node.ClockSkew{Offset: 30 * time.Second, Drift: 0.5})
+	networkCfg, nodes := launchFakeNetwork(t, networkCfg)
+	launchTime := networkCfg.Nodes[0].GetGenesis().LaunchTime
+
+	// node3 proposes two blocks with its skewed clock, the others accept them
+	nodes["node3"].ProduceBlocks(2)
+	nodes["node1"].SyncFrom(nodes["node3"])
+	nodes["node2"].SyncFrom(nodes["node3"])
+	require.NoError(t, networkCfg.HealthCheck(2, 100*time.Millisecond))
+
+	timestamps, err := networkCfg.BlockTimestamps("best")
+	require.NoError(t, err)
+	skewed := launchTime + 20 + 30 + 10
+	assert.Equal(t, map[string]uint64{"node1": skewed, "node2": skewed, "node3": skewed}, timestamps)
+
+	// Competing proposals show both timestamps in the health check
+	nodes["node1"].ProduceBlocks(1)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	require.NoError(t, nodes["node3"].Fork(2, "skewed", 1))
+	require.ErrorContains(t, networkCfg.HealthCheck(3, 100*time.Millisecond),
+		fmt.Sprintf("(timestamp %d)", launchTime+30+30+15))
+}
+
+func TestFakeNodeAPI(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+	fakeNode := nodes["node1"]
+	thorClient := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr())
+
+	fakeNode.ProduceBlocks(5)
+	require.NoError(t, fakeNode.Finalize(2))
+
+	t.Run("Revisions", func(t *testing.T) {
+		best, err := thorClient.Block("best")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(5), best.Number)
+		assert.False(t, best.IsFinalized)
+
+		finalized, err := thorClient.Block("finalized")
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), finalized.Number)
+		assert.True(t, finalized.IsFinalized)
+
+		byID, err := thorClient.Block(best.ParentID.String())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(4), byID.Number)
+
+		_, err = thorClient.Block("6")
+		assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	})
+
+	t.Run("Accounts", func(t *testing.T) {
+		genesisAccount := networkCfg.Nodes[0].GetGenesis().Accounts[0]
+		account, err := thorClient.Account(&genesisAccount.Address)
+		require.NoError(t, err)
+		assert.Equal(t, (*big.Int)(genesisAccount.Balance), (*big.Int)(account.Balance))
+
+		addr := thor.BytesToAddress([]byte("scripted"))
+		fakeNode.SetAccount(addr, &api.Account{
+			Balance: (*math.HexOrDecimal256)(big.NewInt(42)),
+			Energy:  (*math.HexOrDecimal256)(big.NewInt(7)),
+			HasCode: true,
+		})
+		account, err = thorClient.Account(&addr)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(42), (*big.Int)(account.Balance))
+		assert.True(t, account.HasCode)
+	})
+
+	t.Run("AutoProduce", func(t *testing.T) {
+		fakeNode.AutoProduce(time.Millisecond)
+		require.Eventually(t, func() bool {
+			best, err := thorClient.Block("best")
+			return err == nil && best.Number >= 10
+		}, time.Second, 5*time.Millisecond)
+		fakeNode.AutoProduce(0)
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		fakeNode.SetOffline(true)
+		_, err := thorClient.Block("best")
+		assert.Error(t, err)
+
+		fakeNode.SetOffline(false)
+		_, err = thorClient.Block("best")
+		assert.NoError(t, err)
+	})
+}
+
+func TestFakeClientAddRemoveNode(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	c, _ := faketest.StartClient(t, networkCfg)
+
+	require.NoError(t, c.AddNode(&node.BaseNode{
+		ID:      "node4",
+		Genesis: networkCfg.Nodes[0].GetGenesis(),
+	}))
+	require.NoError(t, networkCfg.HealthCheck(0, 100*time.Millisecond))
+
+	require.NoError(t, c.RemoveNode("node4"))
+	require.NoError(t, networkCfg.HealthCheck(0, 100*time.Millisecond))
+
+	nodes, err := c.Nodes()
+	require.NoError(t, err)
+	assert.Len(t, nodes, 3)
+}
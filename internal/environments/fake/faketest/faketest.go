@@ -0,0 +1,41 @@
+// Package faketest starts networks on fake nodes for tests of the packages built on the client.
+package faketest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/client"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/fake"
+	"github.com/vechain/networkhub/network"
+)
+
+// UseFake switches the network to fake nodes listening on ephemeral ports
+func UseFake(networkCfg *network.Network) {
+	networkCfg.Environment = environments.Fake
+	for _, nodeCfg := range networkCfg.Nodes {
+		nodeCfg.SetAPIAddr("")
+	}
+}
+
+// StartClient starts the network on fake nodes through a client that is stopped when the test ends
+func StartClient(t testing.TB, networkCfg *network.Network) (*client.Client, map[string]*fake.Node) {
+	t.Helper()
+	UseFake(networkCfg)
+
+	c, err := client.New(networkCfg)
+	require.NoError(t, err)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, c.Stop())
+	})
+
+	lifecycles, err := c.Nodes()
+	require.NoError(t, err)
+	nodes := make(map[string]*fake.Node)
+	for id, instance := range lifecycles {
+		nodes[id] = instance.(*fake.Node)
+	}
+	return c, nodes
+}
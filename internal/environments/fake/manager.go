@@ -0,0 +1,120 @@
+package fake
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/thorbuilder"
+)
+
+// Manager handles fake node management utilities.
+// Fake nodes run no thor at all: each one serves a scripted subset of the thor REST API
+// from an httptest server, so launcher, health-check and client logic can be tested in milliseconds.
+type Manager struct {
+	mu    sync.Mutex
+	nodes map[string]*Node
+}
+
+// NewManager creates a new fake node manager
+func NewManager() *Manager {
+	return &Manager{
+		nodes: make(map[string]*Node),
+	}
+}
+
+// StartNode starts serving the fake API for a node
+func (m *Manager) StartNode(nodeCfg node.Config, networkCfg *network.Network, _ []string) (node.Lifecycle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodeInstance := NewFakeNode(nodeCfg, networkCfg, m.lookup)
+	if err := nodeInstance.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start fake node %s: %w", nodeCfg.GetID(), err)
+	}
+
+	m.nodes[nodeCfg.GetID()] = nodeInstance
+	return nodeInstance, nil
+}
+
+// StopNode stops serving the fake API for a node
+func (m *Manager) StopNode(nodeInstance node.Lifecycle) error {
+	if fakeNode, ok := nodeInstance.(*Node); ok {
+		m.mu.Lock()
+		delete(m.nodes, fakeNode.nodeCfg.GetID())
+		m.mu.Unlock()
+	}
+	return nodeInstance.Stop()
+}
+
+// BuildThorBinary is a no-op as fake nodes do not run thor
+func (m *Manager) BuildThorBinary(_ *thorbuilder.Config) (string, error) {
+	return "", nil
+}
+
+// ValidateNode validates a node configuration, fake nodes accept any configuration
+func (m *Manager) ValidateNode(_ node.Config) error {
+	return nil
+}
+
+// GenerateEnodes creates loopback enode strings for nodes that have a key
+func (m *Manager) GenerateEnodes(networkCfg *network.Network) ([]string, error) {
+	var enodes []string
+	for _, node := range networkCfg.Nodes {
+		if node.GetKey() == "" {
+			continue
+		}
+		enode, err := node.Enode("127.0.0.1")
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate enode for node %s: %w", node.GetID(), err)
+		}
+		enodes = append(enodes, enode)
+	}
+	return enodes, nil
+}
+
+// Cleanup is a no-op as fake nodes release their servers on stop
+func (m *Manager) Cleanup() error {
+	return nil
+}
+
+// Capabilities lists the optional features of the fake environment, public networks are simulated too
+func (m *Manager) Capabilities() []environments.Capability {
+	return []environments.Capability{
+		environments.CapabilityPublicNetworks,
+		environments.CapabilityPartition,
+		environments.CapabilityPause,
+		environments.CapabilityClockSkew,
+		environments.CapabilityMetrics,
+	}
+}
+
+// Partition only checks the nodes are running, fake nodes derive their default peers from the
+// network's partition and tests script each side's chain
+func (m *Manager) Partition(groups ...[]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, group := range groups {
+		for _, id := range group {
+			if _, ok := m.nodes[id]; !ok {
+				return fmt.Errorf("node %s is not running", id)
+			}
+		}
+	}
+	return nil
+}
+
+// Heal is a no-op, fake nodes go back to their default peers once the network partition is cleared
+func (m *Manager) Heal() error {
+	return nil
+}
+
+// lookup returns the running fake node with the given ID, or nil
+func (m *Manager) lookup(id string) *Node {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodes[id]
+}
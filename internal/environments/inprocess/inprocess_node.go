@@ -0,0 +1,392 @@
+package inprocess
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/httpserver"
+	thornode "github.com/vechain/thor/v2/cmd/thor/node"
+	"github.com/vechain/thor/v2/cmd/thor/p2p"
+	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/consensus"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+// thorVersion is reported to peers by in-process nodes
+const thorVersion = "networkhub-inprocess"
+
+// Node runs a thor node inside the current process.
+// It wires up the same components the thor binary does: chain repository, tx pool,
+// p2p communicator, BFT engine, consensus, packer and the HTTP API server.
+type Node struct {
+	nodeCfg    node.Config
+	networkCfg *network.Network
+	enodes     []string
+
+	cancel  context.CancelFunc
+	done    chan struct{}
+	closers []func()
+}
+
+func NewInProcessNode(nodeCfg node.Config, networkCfg *network.Network, enodes []string) *Node {
+	return &Node{
+		nodeCfg:    nodeCfg,
+		networkCfg: networkCfg,
+		enodes:     enodes,
+	}
+}
+
+func (n *Node) Start() error {
+	if n.cancel != nil {
+		return fmt.Errorf("node %s is already running", n.nodeCfg.GetID())
+	}
+
+	if err := n.start(); err != nil {
+		n.close()
+		return err
+	}
+	return nil
+}
+
+func (n *Node) Stop() error {
+	if n.cancel == nil {
+		return nil
+	}
+
+	n.cancel()
+	select {
+	case <-n.done:
+	case <-time.After(10 * time.Second):
+		// The databases are only closed once Run returns, closing them under a running node corrupts them
+		slog.Warn("node is slow to stop, waiting for it before closing its databases", "id", n.nodeCfg.GetID())
+		<-n.done
+	}
+	slog.Info("node stopped gracefully", "id", n.nodeCfg.GetID())
+
+	n.close()
+	n.cancel = nil
+	return nil
+}
+
+// start wires the thor components together, registering a closer for each of them
+func (n *Node) start() error {
+	if err := n.prepareDataDir(); err != nil {
+		return fmt.Errorf("failed to prepare data dir: %w", err)
+	}
+
+	gene, forkConfig, err := n.buildGenesis()
+	if err != nil {
+		return fmt.Errorf("failed to build genesis: %w", err)
+	}
+
+	instanceDir := filepath.Join(n.nodeCfg.GetDataDir(), fmt.Sprintf("instance-%x", gene.ID().Bytes()[24:]))
+	if err := os.MkdirAll(instanceDir, 0700); err != nil {
+		return fmt.Errorf("unable to create instance dir: %w", err)
+	}
+
+	mainDB, err := muxdb.Open(filepath.Join(instanceDir, "main.db"), &muxdb.Options{
+		TrieNodeCacheSizeMB:        128,
+		TrieCachedNodeTTL:          30,
+		TrieDedupedPartitionFactor: math.MaxUint32,
+		TrieHistPartitionFactor:    524288,
+		OpenFilesCacheCapacity:     64,
+		ReadCacheMB:                16,
+		WriteBufferMB:              16,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open main database: %w", err)
+	}
+	n.addCloser(func() { mainDB.Close() })
+
+	logDB, err := logdb.New(filepath.Join(instanceDir, "logs-v2.db"), false)
+	if err != nil {
+		return fmt.Errorf("failed to open log database: %w", err)
+	}
+	n.addCloser(func() { logDB.Close() })
+
+	repo, err := initChainRepository(gene, mainDB, logDB)
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.HexToECDSA(n.nodeCfg.GetKey())
+	if err != nil {
+		return fmt.Errorf("unable to process key for node %s: %w", n.nodeCfg.GetID(), err)
+	}
+	master := &thornode.Master{PrivateKey: key}
+
+	txPool := txpool.New(repo, state.NewStater(mainDB), txpool.Options{
+		Limit:           10000,
+		LimitPerAccount: 128,
+		MaxLifetime:     20 * time.Minute,
+	}, forkConfig)
+	n.addCloser(txPool.Close)
+
+	bootnodes, err := n.bootnodes()
+	if err != nil {
+		return fmt.Errorf("failed to parse bootnodes: %w", err)
+	}
+	// A declared topology pins the peers, bootnodes would let discovery connect everyone
+	var allowedPeers []*discover.Node
+	if n.networkCfg.Topology != nil {
+		allowedPeers, bootnodes = bootnodes, nil
+	}
+
+	p2pCommunicator := p2p.New(
+		comm.New(repo, txPool),
+		key,
+		instanceDir,
+		nil, // no NAT on loopback
+		thorVersion,
+		25,
+		n.nodeCfg.GetP2PListenPort(),
+		fmt.Sprintf("127.0.0.1:%d", n.nodeCfg.GetP2PListenPort()),
+		allowedPeers,
+		nil,
+		bootnodes,
+	)
+
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, master.Address())
+	if err != nil {
+		return fmt.Errorf("failed to init bft engine: %w", err)
+	}
+
+	apiURL, srvCloser, err := httpserver.StartAPIServer(
+		n.apiListenAddr(),
+		repo,
+		state.NewStater(mainDB),
+		txPool,
+		logDB,
+		bftEngine,
+		p2pCommunicator.Communicator(),
+		forkConfig,
+		n.apiConfig(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start API server: %w", err)
+	}
+	n.addCloser(srvCloser)
+
+	// Record the ephemeral API port so the node's HTTP address can be used by clients
+	if err := n.updateAPIAddr(apiURL); err != nil {
+		return err
+	}
+
+	if err := p2pCommunicator.Start(); err != nil {
+		return fmt.Errorf("failed to start p2p communicator: %w", err)
+	}
+	n.addCloser(p2pCommunicator.Stop)
+
+	stater := state.NewStater(mainDB)
+	thorNode := thornode.New(
+		master,
+		repo,
+		bftEngine,
+		stater,
+		logDB,
+		txPool,
+		filepath.Join(instanceDir, "tx.stash"),
+		p2pCommunicator.Communicator(),
+		forkConfig,
+		thornode.Options{},
+		consensus.New(repo, stater, forkConfig),
+		packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig, 0),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+	n.done = make(chan struct{})
+	go func() {
+		defer close(n.done)
+		if err := thorNode.Run(ctx); err != nil {
+			slog.Error("in-process node exited with error", "id", n.nodeCfg.GetID(), "error", err)
+		}
+	}()
+
+	slog.Info("started node", "id", n.nodeCfg.GetID(), "api", n.nodeCfg.GetHTTPAddr(), "p2pPort", n.nodeCfg.GetP2PListenPort())
+	return nil
+}
+
+// addCloser registers a function run when the node stops
+func (n *Node) addCloser(fn func()) {
+	n.closers = append(n.closers, fn)
+}
+
+// close runs the registered closers in reverse order
+func (n *Node) close() {
+	for i := len(n.closers) - 1; i >= 0; i-- {
+		n.closers[i]()
+	}
+	n.closers = nil
+	if n.cancel != nil {
+		n.cancel()
+	}
+}
+
+// prepareDataDir cleans the data directory unless the node is persistent
+func (n *Node) prepareDataDir() error {
+	if !n.nodeCfg.IsPersistent() {
+		if err := os.RemoveAll(n.nodeCfg.GetDataDir()); err != nil {
+			return fmt.Errorf("failed to remove data dir: %w", err)
+		}
+	}
+	return os.MkdirAll(n.nodeCfg.GetDataDir(), 0777)
+}
+
+// bootnodes parses the enodes of the other nodes in the network
+func (n *Node) bootnodes() ([]*discover.Node, error) {
+	ownEnode, err := n.nodeCfg.Enode("127.0.0.1")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*discover.Node
+	for _, enode := range n.enodes {
+		if enode == ownEnode {
+			continue
+		}
+		parsed, err := discover.ParseNode(enode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enode %s: %w", enode, err)
+		}
+		nodes = append(nodes, parsed)
+	}
+	return nodes, nil
+}
+
+// apiListenAddr returns the API listen address, using an ephemeral loopback port when none is configured
+func (n *Node) apiListenAddr() string {
+	if n.nodeCfg.GetAPIAddr() == "" {
+		return "127.0.0.1:0"
+	}
+	return n.nodeCfg.GetAPIAddr()
+}
+
+// updateAPIAddr stores the address the API server actually listens on
+func (n *Node) updateAPIAddr(apiURL string) error {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse API URL %s: %w", apiURL, err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return fmt.Errorf("unable to parse API address %s: %w", u.Host, err)
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	n.nodeCfg.SetAPIAddr(net.JoinHostPort(host, port))
+	return nil
+}
+
+// apiConfig mirrors the thor binary's API flag defaults
+func (n *Node) apiConfig() httpserver.APIConfig {
+	var allowedTracers []string
+	if tracers, ok := n.nodeCfg.GetAdditionalArgs()["api-allowed-tracers"]; ok {
+		for _, tracer := range strings.Split(tracers, ",") {
+			allowedTracers = append(allowedTracers, strings.TrimSpace(tracer))
+		}
+	}
+
+	return httpserver.APIConfig{
+		AllowedOrigins:             n.nodeCfg.GetAPICORS(),
+		BacktraceLimit:             1000,
+		CallGasLimit:               50_000_000,
+		BatchDataMaxSize:           5 * 1024 * 1024 / 2,
+		EnableReqLogger:            &atomic.Bool{},
+		LogsLimit:                  1000,
+		AllowedTracers:             allowedTracers,
+		APIBacktraceLimit:          1000,
+		PriorityIncreasePercentage: 5,
+		Timeout:                    10000,
+	}
+}
+
+// buildGenesis converts the node genesis into a thor genesis the same way the thor binary parses a genesis file.
+// The genesis config is held in thor's process-wide configuration until the node stops.
+func (n *Node) buildGenesis() (*thorgenesis.Genesis, *thor.ForkConfig, error) {
+	gen, forkConfig, err := nodegenesis.ToThor(n.nodeCfg.GetGenesis())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release, err := nodegenesis.HoldConfig(gen.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.addCloser(release)
+	// HoldConfig applied the config, NewCustomNet would set it again while other nodes read it
+	gen.Config = nil
+	gene, err := thorgenesis.NewCustomNet(gen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gene, forkConfig, nil
+}
+
+// initChainRepository builds the genesis block and writes its logs
+func initChainRepository(gene *thorgenesis.Genesis, mainDB *muxdb.MuxDB, logDB *logdb.LogDB) (*chain.Repository, error) {
+	genesisBlock, genesisEvents, genesisTransfers, err := gene.Build(state.NewStater(mainDB))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build genesis block: %w", err)
+	}
+
+	repo, err := chain.NewRepository(mainDB, genesisBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize block chain: %w", err)
+	}
+
+	w := logDB.NewWriter()
+	if err := w.Write(genesisBlock, tx.Receipts{{
+		Outputs: []*tx.Output{
+			{Events: genesisEvents, Transfers: genesisTransfers},
+		},
+	}}); err != nil {
+		return nil, fmt.Errorf("failed to write genesis logs: %w", err)
+	}
+	if err := w.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit genesis logs: %w", err)
+	}
+
+	return repo, nil
+}
+
+// freePort asks the kernel for a free loopback TCP port
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
@@ -0,0 +1,212 @@
+package inprocess_test
+
+import (
+	"context"
+	"encoding/hex"
+	"maps"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/genesisbuilder"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/inprocess"
+	"github.com/vechain/networkhub/internal/environments/launcher"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestInProcessNetwork(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.InProcess
+
+	// Let the environment pick ephemeral ports and temporary data dirs
+	for _, node := range networkCfg.Nodes {
+		node.SetAPIAddr("")
+		node.SetP2PListenPort(0)
+		node.SetDataDir(t.TempDir())
+	}
+
+	env, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, env.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, env.StopNetwork())
+	})
+
+	require.NoError(t, networkCfg.HealthCheck(1, time.Minute))
+
+	best, err := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr()).Block("best")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, best.Number, uint32(1))
+}
+
+func TestInProcessLineTopology(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.InProcess
+	networkCfg.Topology = &network.Topology{Shape: network.TopologyLine}
+
+	for _, node := range networkCfg.Nodes {
+		node.SetAPIAddr("")
+		node.SetP2PListenPort(0)
+		node.SetDataDir(t.TempDir())
+	}
+
+	env, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, env.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, env.StopNetwork())
+	})
+
+	// Blocks still propagate from end to end through the middle node
+	require.NoError(t, networkCfg.HealthCheck(1, time.Minute))
+
+	peers, err := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr()).Peers()
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	require.Equal(t, networkCfg.Nodes[1].PeerID(), peers[0].PeerID)
+}
+
+func TestInProcessRejectsPublicNetworks(t *testing.T) {
+	networkCfg, err := preset.NewTestnetNetwork()
+	require.NoError(t, err)
+	networkCfg.Environment = environments.InProcess
+	networkCfg.Nodes = preset.LocalThreeNodesNetwork().Nodes
+
+	env, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, env.StartNetwork(), "does not support public networks")
+}
+
+func TestInProcessCapabilities(t *testing.T) {
+	// The capabilities match what the nodes implement
+	var instance node.Lifecycle = &inprocess.Node{}
+	capabilities := inprocess.NewManager().Capabilities()
+	_, isOperator := instance.(node.Operator)
+	require.Equal(t, isOperator, slices.Contains(capabilities, environments.CapabilityExec))
+	_, isPauser := instance.(node.Pauser)
+	require.Equal(t, isPauser, slices.Contains(capabilities, environments.CapabilityPause))
+}
+
+func TestInProcessRejectsAnotherConfig(t *testing.T) {
+	startNetwork := func(blockInterval uint64) error {
+		networkCfg := preset.LocalThreeNodesNetwork()
+		networkCfg.Environment = environments.InProcess
+		networkCfg.Nodes[0].GetGenesis().Config.BlockInterval = blockInterval
+		for _, node := range networkCfg.Nodes {
+			node.SetAPIAddr("")
+			node.SetP2PListenPort(0)
+			node.SetDataDir(t.TempDir())
+		}
+
+		env, err := launcher.New(networkCfg)
+		require.NoError(t, err)
+		if err := env.StartNetwork(); err != nil {
+			return err
+		}
+		t.Cleanup(func() {
+			require.NoError(t, env.StopNetwork())
+		})
+		return nil
+	}
+
+	require.NoError(t, startNetwork(10))
+	// Thor's config is process-wide, a second network can only run on the same one
+	require.NoError(t, startNetwork(10))
+	require.ErrorContains(t, startNetwork(5), "in-process nodes are running on another thor configuration")
+}
+
+func TestInProcessGenesisContract(t *testing.T) {
+	// balanceOf(address) reading mapping(address => uint256) at slot 0, as solc lays it out
+	artifact, err := genesisbuilder.ParseArtifact([]byte(`{"deployedBytecode":"0x600435600052600060205260406000205460005260206000f3"}`))
+	require.NoError(t, err)
+	token := thor.MustParseAddress("0x0000000000000000000000000000000000001000")
+	holder := thor.MustParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+
+	networkCfg := preset.LocalThreeNodesNetwork()
+	networkCfg.Environment = environments.InProcess
+	gen := networkCfg.Nodes[0].GetGenesis()
+	gen.Accounts = append(gen.Accounts, genesisbuilder.Contract{
+		Address:  token,
+		Artifact: artifact,
+		Storage:  genesisbuilder.OpenZeppelinERC20.Storage(genesisbuilder.ERC20Token{Balances: map[thor.Address]*big.Int{holder: big.NewInt(1234)}}),
+	}.Account())
+
+	for _, node := range networkCfg.Nodes {
+		node.SetAPIAddr("")
+		node.SetP2PListenPort(0)
+		node.SetDataDir(t.TempDir())
+	}
+
+	env, err := launcher.New(networkCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, env.StartNetwork())
+	t.Cleanup(func() {
+		require.NoError(t, env.StopNetwork())
+	})
+
+	results, err := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr()).InspectClauses(&api.BatchCallData{
+		Clauses: api.Clauses{{To: &token, Data: "0x70a08231" + hex.EncodeToString(thor.BytesToBytes32(holder.Bytes()).Bytes())}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].Reverted, results[0].VMError)
+	require.Equal(t, thor.BytesToBytes32(big.NewInt(1234).Bytes()).String(), results[0].Data)
+
+	// Thor lists storage before a transaction, so the latest state cannot be listed yet
+	_, err = networkCfg.ExportAccounts("best", token)
+	require.ErrorContains(t, err, "cannot list the storage of contract "+token.String())
+
+	// The contract exports back with its code and the storage the genesis set, read at the block before the transfer
+	receipt := sendTransfer(t, networkCfg, holder)
+	exported, err := networkCfg.ExportAccounts(strconv.FormatUint(uint64(receipt.Meta.BlockNumber-1), 10), token)
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	require.Equal(t, "0x"+hex.EncodeToString(artifact.DeployedBytecode), exported[0].Code)
+	// Zero slots are not stored in the state
+	storage := maps.Clone(gen.Accounts[len(gen.Accounts)-1].Storage)
+	maps.DeleteFunc(storage, func(_ string, value thor.Bytes32) bool { return value.IsZero() })
+	require.Equal(t, storage, exported[0].Storage)
+}
+
+// sendTransfer sends 1 VET from the first node's master account and waits for its receipt
+func sendTransfer(t *testing.T, networkCfg *network.Network, to thor.Address) *api.Receipt {
+	client := thorclient.New(networkCfg.Nodes[0].GetHTTPAddr())
+	tag, err := client.ChainTag()
+	require.NoError(t, err)
+
+	trx := new(tx.Builder).
+		ChainTag(tag).
+		Expiration(math.MaxUint32).
+		Gas(21_000).
+		GasPriceCoef(128).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1e18))).
+		Build()
+	key, err := crypto.HexToECDSA(networkCfg.Nodes[0].GetKey())
+	require.NoError(t, err)
+	signature, err := crypto.Sign(trx.SigningHash().Bytes(), key)
+	require.NoError(t, err)
+	sent, err := client.SendTransaction(trx.WithSignature(signature))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	receipt, err := networkCfg.WaitForTxReceipt(ctx, *sent.ID)
+	require.NoError(t, err)
+	return receipt
+}
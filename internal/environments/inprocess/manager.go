@@ -0,0 +1,119 @@
+package inprocess
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/networkhub/thorbuilder"
+)
+
+// Manager handles in-process node management utilities.
+// Nodes run inside the current process using the thor packages this module depends on,
+// with real p2p over loopback and the real HTTP API, so no thor build or Docker daemon is needed.
+// Thor keeps its chain config (block interval, epoch length...) process-wide, so all in-process
+// nodes must share the same genesis config.
+type Manager struct {
+	mu sync.Mutex
+}
+
+// NewManager creates a new in-process node manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// StartNode starts a node inside the current process
+func (m *Manager) StartNode(nodeCfg node.Config, networkCfg *network.Network, enodes []string) (node.Lifecycle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodeInstance := NewInProcessNode(nodeCfg, networkCfg, enodes)
+	if err := nodeInstance.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start in-process node %s: %w", nodeCfg.GetID(), err)
+	}
+
+	return nodeInstance, nil
+}
+
+// StopNode stops an in-process node
+func (m *Manager) StopNode(nodeInstance node.Lifecycle) error {
+	return nodeInstance.Stop()
+}
+
+// BuildThorBinary is a no-op as in-process nodes are compiled into the current binary
+func (m *Manager) BuildThorBinary(_ *thorbuilder.Config) (string, error) {
+	return "", nil
+}
+
+// ValidateNode validates and sets defaults for a node configuration
+func (m *Manager) ValidateNode(nodeCfg node.Config) error {
+	if nodeCfg.GetKey() == "" {
+		return fmt.Errorf("key cannot be empty for node %s", nodeCfg.GetID())
+	}
+	if nodeCfg.GetGenesis() == nil {
+		return fmt.Errorf("genesis cannot be empty for node %s", nodeCfg.GetID())
+	}
+	// Thor's config is process-wide, the node cannot run next to in-process nodes on another one
+	gen, _, err := nodegenesis.ToThor(nodeCfg.GetGenesis())
+	if err != nil {
+		return fmt.Errorf("invalid genesis for node %s: %w", nodeCfg.GetID(), err)
+	}
+	if err := nodegenesis.CheckHeldConfig(gen.Config); err != nil {
+		return fmt.Errorf("node %s cannot run in-process: %w", nodeCfg.GetID(), err)
+	}
+
+	// Set default directories if not configured
+	if nodeCfg.GetDataDir() == "" {
+		dataDir, err := os.MkdirTemp("", fmt.Sprintf("networkhub_inprocess_%s_", nodeCfg.GetID()))
+		if err != nil {
+			return fmt.Errorf("unable to create data dir: %w", err)
+		}
+		nodeCfg.SetDataDir(dataDir)
+	}
+	if nodeCfg.GetConfigDir() == "" {
+		nodeCfg.SetConfigDir(nodeCfg.GetDataDir())
+	}
+
+	return nil
+}
+
+// Cleanup is a no-op as in-process nodes release their resources on stop
+func (m *Manager) Cleanup() error {
+	return nil
+}
+
+// Capabilities lists the optional features of the in-process environment. It has none: nodes run no
+// thor process, so they implement neither node.Operator nor node.Pauser, and expose no metrics.
+func (m *Manager) Capabilities() []environments.Capability {
+	return nil
+}
+
+// GenerateEnodes creates loopback enode strings for all nodes, allocating free p2p ports where none is set
+func (m *Manager) GenerateEnodes(networkCfg *network.Network) ([]string, error) {
+	if networkCfg.IsPublicNetwork() {
+		return nil, fmt.Errorf("in-process environment does not support public networks")
+	}
+
+	var enodes []string
+	for _, node := range networkCfg.Nodes {
+		if node.GetP2PListenPort() == 0 {
+			port, err := freePort()
+			if err != nil {
+				return nil, fmt.Errorf("failed to allocate p2p port for node %s: %w", node.GetID(), err)
+			}
+			node.SetP2PListenPort(port)
+		}
+
+		enode, err := node.Enode("127.0.0.1")
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate enode for node %s: %w", node.GetID(), err)
+		}
+		enodes = append(enodes, enode)
+	}
+
+	return enodes, nil
+}
@@ -2,11 +2,14 @@ package launcher
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
 	"sync"
 
 	"github.com/vechain/networkhub/internal/environments"
-	"github.com/vechain/networkhub/internal/environments/docker"
-	"github.com/vechain/networkhub/internal/environments/local"
+	"github.com/vechain/networkhub/metrics"
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
 )
@@ -16,11 +19,14 @@ type Launcher struct {
 	// Central state management
 	networkCfg *network.Network
 	nodes      map[string]node.Lifecycle
-	started    bool
+	// nodes killed by StopNode, kept by environments implementing Restarter until StartNode restarts them
+	stopped map[string]node.Lifecycle
+	started bool
 
-	// Infrastructure utilities
-	dockerManager *docker.Manager
-	localManager  *local.Manager
+	// Environment managers resolved from the registry by name. The network's environment
+	// is always present, nodes overriding their environment add others.
+	managers map[string]environments.Manager
+	nodeEnvs map[string]string
 
 	mu sync.Mutex
 }
@@ -31,23 +37,18 @@ func New(cfg *network.Network) (*Launcher, error) {
 		return nil, fmt.Errorf("network configuration cannot be nil")
 	}
 
+	manager, err := environments.NewManager(cfg.Environment, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s environment: %w", cfg.Environment, err)
+	}
+
 	launcher := &Launcher{
 		networkCfg: cfg,
 		nodes:      make(map[string]node.Lifecycle),
+		stopped:    make(map[string]node.Lifecycle),
 		started:    false,
-	}
-
-	// Initialize the appropriate managers based on environment type
-	switch cfg.Environment {
-	case environments.Local:
-		launcher.localManager = local.NewManager()
-	case environments.Docker:
-		launcher.dockerManager = docker.NewManager()
-		if err := launcher.dockerManager.Initialize(cfg); err != nil {
-			return nil, fmt.Errorf("failed to initialize Docker manager: %w", err)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported environment: %s", cfg.Environment)
+		managers:   map[string]environments.Manager{cfg.Environment: manager},
+		nodeEnvs:   make(map[string]string),
 	}
 
 	return launcher, nil
@@ -62,6 +63,10 @@ func (l *Launcher) StartNetwork() error {
 		return fmt.Errorf("network is already running")
 	}
 
+	if err := l.initManagers(); err != nil {
+		return err
+	}
+
 	if len(l.networkCfg.Nodes) == 0 {
 		// For public networks, it's valid to start with 0 nodes and add them later
 		if !l.networkCfg.IsPublicNetwork() {
@@ -77,6 +82,13 @@ func (l *Launcher) StartNetwork() error {
 		return fmt.Errorf("failed to build thor binary: %w", err)
 	}
 
+	// A wrong genesis would only show as nodes that never produce blocks
+	if !l.networkCfg.SkipGenesisCheck {
+		if _, err := l.networkCfg.CheckGenesis(); err != nil {
+			return err
+		}
+	}
+
 	// Generate enodes for faster p2p bootstrap
 	enodes, err := l.generateEnodes()
 	if err != nil {
@@ -90,22 +102,19 @@ func (l *Launcher) StartNetwork() error {
 			return fmt.Errorf("failed to validate node %s: %w", nodeCfg.GetID(), err)
 		}
 
-		// Start the node using the appropriate manager
-		var nodeInstance node.Lifecycle
-		switch l.networkCfg.Environment {
-		case environments.Local:
-			nodeInstance, err = l.localManager.StartNode(nodeCfg, l.networkCfg, enodes)
-		case environments.Docker:
-			nodeInstance, err = l.dockerManager.StartNode(nodeCfg, l.networkCfg, enodes)
-		default:
-			return fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
+		// Start the node using the manager of its environment
+		env := l.environmentOf(nodeCfg)
+		bootnodes, err := l.peerEnodes(nodeCfg, enodes[env])
+		if err != nil {
+			return err
 		}
-
+		nodeInstance, err := l.managers[env].StartNode(nodeCfg, l.networkCfg, bootnodes)
 		if err != nil {
 			return fmt.Errorf("unable to start node %s: %w", nodeCfg.GetID(), err)
 		}
 
 		l.nodes[nodeCfg.GetID()] = nodeInstance
+		l.nodeEnvs[nodeCfg.GetID()] = env
 	}
 
 	l.started = true
@@ -123,20 +132,27 @@ func (l *Launcher) StopNetwork() error {
 
 	var lastErr error
 	for nodeID, nodeInstance := range l.nodes {
-		if err := l.stopNode(nodeInstance); err != nil {
+		if err := l.stopNode(nodeID, nodeInstance); err != nil {
+			lastErr = fmt.Errorf("failed to stop node %s: %w", nodeID, err)
+		}
+	}
+	// Killed nodes still hold resources such as their container
+	for nodeID, nodeInstance := range l.stopped {
+		if err := l.stopNode(nodeID, nodeInstance); err != nil {
 			lastErr = fmt.Errorf("failed to stop node %s: %w", nodeID, err)
 		}
 	}
 
 	l.nodes = make(map[string]node.Lifecycle)
+	l.stopped = make(map[string]node.Lifecycle)
+	l.nodeEnvs = make(map[string]string)
 	l.started = false
+	l.networkCfg.SetPartition(nil)
 
-	// Clean up Docker resources if using Docker environment
-	if l.networkCfg.Environment == environments.Docker && l.dockerManager != nil {
-		if err := l.dockerManager.Cleanup(); err != nil {
-			if lastErr == nil {
-				lastErr = fmt.Errorf("failed to cleanup Docker resources: %w", err)
-			}
+	// Release environment resources such as Docker networks
+	for env, manager := range l.managers {
+		if err := manager.Cleanup(); err != nil && lastErr == nil {
+			lastErr = fmt.Errorf("failed to cleanup %s environment: %w", env, err)
 		}
 	}
 
@@ -161,38 +177,9 @@ func (l *Launcher) AddNode(nodeConfig node.Config) error {
 
 	// If network is running, start the new node immediately
 	if l.started {
-		// Build Thor binary if needed
-		if err := l.buildThorBinaryIfNeeded(); err != nil {
-			return fmt.Errorf("failed to build thor binary for new node: %w", err)
-		}
-
-		// Validate the node before starting
-		if err := l.validateNode(nodeConfig); err != nil {
-			return fmt.Errorf("failed to validate node %s: %w", nodeConfig.GetID(), err)
-		}
-
-		// Generate fresh enodes including the new node
-		enodes, err := l.generateEnodes()
-		if err != nil {
-			return fmt.Errorf("failed to generate enodes: %w", err)
-		}
-
-		// Start the node using the appropriate manager
-		var nodeInstance node.Lifecycle
-		switch l.networkCfg.Environment {
-		case environments.Local:
-			nodeInstance, err = l.localManager.StartNode(nodeConfig, l.networkCfg, enodes)
-		case environments.Docker:
-			nodeInstance, err = l.dockerManager.StartNode(nodeConfig, l.networkCfg, enodes)
-		default:
-			return fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
-		}
-
-		if err != nil {
+		if err := l.startNode(nodeConfig); err != nil {
 			return fmt.Errorf("unable to start node %s after adding: %w", nodeConfig.GetID(), err)
 		}
-
-		l.nodes[nodeConfig.GetID()] = nodeInstance
 	}
 
 	return nil
@@ -204,6 +191,9 @@ func (l *Launcher) RemoveNode(nodeID string) error {
 	defer l.mu.Unlock()
 
 	nodeInstance, exists := l.nodes[nodeID]
+	if !exists {
+		nodeInstance, exists = l.stopped[nodeID]
+	}
 	if !exists {
 		return fmt.Errorf("node with ID %s does not exist", nodeID)
 	}
@@ -221,17 +211,99 @@ func (l *Launcher) RemoveNode(nodeID string) error {
 	}
 
 	// Stop the node
-	if err := l.stopNode(nodeInstance); err != nil {
+	if err := l.stopNode(nodeID, nodeInstance); err != nil {
 		return fmt.Errorf("unable to stop node %s: %w", nodeID, err)
 	}
 
 	// Remove from configuration and tracking
 	l.networkCfg.Nodes = append(l.networkCfg.Nodes[:index], l.networkCfg.Nodes[index+1:]...)
 	delete(l.nodes, nodeID)
+	delete(l.stopped, nodeID)
+	delete(l.nodeEnvs, nodeID)
 
 	return nil
 }
 
+// StopNode stops a running node but keeps it in the network, StartNode brings it back.
+// Environments implementing Restarter kill the node and keep its data, so it restarts where it stopped.
+func (l *Launcher) StopNode(nodeID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	nodeInstance, exists := l.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node with ID %s is not running", nodeID)
+	}
+
+	if restarter, ok := l.managers[l.nodeEnvs[nodeID]].(environments.Restarter); ok {
+		if err := restarter.KillNode(nodeInstance); err != nil {
+			return fmt.Errorf("unable to kill node %s: %w", nodeID, err)
+		}
+		delete(l.nodes, nodeID)
+		l.stopped[nodeID] = nodeInstance
+		return nil
+	}
+
+	if err := l.stopNode(nodeID, nodeInstance); err != nil {
+		return fmt.Errorf("unable to stop node %s: %w", nodeID, err)
+	}
+	delete(l.nodes, nodeID)
+	delete(l.nodeEnvs, nodeID)
+	return nil
+}
+
+// StartNode starts a stopped node of the running network
+func (l *Launcher) StartNode(nodeID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.started {
+		return fmt.Errorf("network is not running")
+	}
+	if _, exists := l.nodes[nodeID]; exists {
+		return fmt.Errorf("node with ID %s is already running", nodeID)
+	}
+
+	if nodeInstance, killed := l.stopped[nodeID]; killed {
+		if err := l.managers[l.nodeEnvs[nodeID]].(environments.Restarter).RestartNode(nodeInstance); err != nil {
+			return fmt.Errorf("unable to restart node %s: %w", nodeID, err)
+		}
+		delete(l.stopped, nodeID)
+		l.nodes[nodeID] = nodeInstance
+		metrics.NodeRestarted(nodeID)
+		return nil
+	}
+
+	for _, nodeCfg := range l.networkCfg.Nodes {
+		if nodeCfg.GetID() == nodeID {
+			if err := l.startNode(nodeCfg); err != nil {
+				return fmt.Errorf("unable to start node %s: %w", nodeID, err)
+			}
+			metrics.NodeRestarted(nodeID)
+			return nil
+		}
+	}
+	return fmt.Errorf("node with ID %s not found in network configuration", nodeID)
+}
+
+// PauseNode freezes a running node without losing its state
+func (l *Launcher) PauseNode(nodeID string) error {
+	pauser, err := l.pauser(nodeID)
+	if err != nil {
+		return err
+	}
+	return pauser.Pause()
+}
+
+// ResumeNode resumes a paused node
+func (l *Launcher) ResumeNode(nodeID string) error {
+	pauser, err := l.pauser(nodeID)
+	if err != nil {
+		return err
+	}
+	return pauser.Resume()
+}
+
 // Nodes returns all nodes in the environment
 func (l *Launcher) Nodes() map[string]node.Lifecycle {
 	l.mu.Lock()
@@ -253,31 +325,298 @@ func (l *Launcher) Config() *network.Network {
 	return l.networkCfg
 }
 
+// Exec runs a command on a running node
+func (l *Launcher) Exec(nodeID string, argv []string) (*node.ExecResult, error) {
+	operator, err := l.operator(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return operator.Exec(argv)
+}
+
+// CopyFrom returns a tar archive of the given path on a running node
+func (l *Launcher) CopyFrom(nodeID, path string) (io.ReadCloser, error) {
+	operator, err := l.operator(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return operator.CopyFrom(path)
+}
+
+// CopyTo extracts a tar archive into the given directory on a running node
+func (l *Launcher) CopyTo(nodeID, path string, archive io.Reader) error {
+	operator, err := l.operator(nodeID)
+	if err != nil {
+		return err
+	}
+	return operator.CopyTo(path, archive)
+}
+
+// Partition splits the running nodes into groups that cannot reach each other, replacing any previous partition
+func (l *Launcher) Partition(groups ...[]string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	partitioner, err := l.partitioner()
+	if err != nil {
+		return err
+	}
+	if err := l.networkCfg.ValidatePartition(groups); err != nil {
+		return err
+	}
+
+	if err := partitioner.Partition(groups...); err != nil {
+		// Nodes partitioned before the failure would stay cut off, roll back to a healed network
+		if healErr := partitioner.Heal(); healErr != nil {
+			return fmt.Errorf("failed to partition network: %w, and failed to roll back: %w", err, healErr)
+		}
+		l.networkCfg.SetPartition(nil)
+		return fmt.Errorf("failed to partition network, rolled back to a healed network: %w", err)
+	}
+	l.networkCfg.SetPartition(groups)
+	return nil
+}
+
+// Heal restores traffic between all nodes after a partition
+func (l *Launcher) Heal() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	partitioner, err := l.partitioner()
+	if err != nil {
+		return err
+	}
+
+	if err := partitioner.Heal(); err != nil {
+		return fmt.Errorf("failed to heal network: %w", err)
+	}
+	l.networkCfg.SetPartition(nil)
+	return nil
+}
+
+// ShapeLinks replaces the delay, jitter, loss and bandwidth of the links between running nodes.
+// Links not covered by shapes are left unshaped, so calling it without shapes resets every link.
+func (l *Launcher) ShapeLinks(shapes ...network.LinkShape) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	shaper, err := l.linkShaper()
+	if err != nil {
+		return err
+	}
+	links, err := l.networkCfg.Links(shapes...)
+	if err != nil {
+		return err
+	}
+
+	if err := shaper.ShapeLinks(links); err != nil {
+		return fmt.Errorf("failed to shape links: %w", err)
+	}
+	return nil
+}
+
+// Supports reports whether the network's environment supports an optional capability
+func (l *Launcher) Supports(capability environments.Capability) bool {
+	return environments.Supports(l.managers[l.networkCfg.Environment], capability)
+}
+
 // Helper methods for orchestration
 
-// buildThorBinaryIfNeeded builds the thor binary if needed and sets exec artifact for nodes
-func (l *Launcher) buildThorBinaryIfNeeded() error {
-	var execPath string
-	var err error
+// operator returns the running node instance as a node.Operator
+func (l *Launcher) operator(nodeID string) (node.Operator, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	nodeInstance, exists := l.nodes[nodeID]
+	if !exists {
+		return nil, fmt.Errorf("node with ID %s is not running", nodeID)
+	}
+
+	env := l.nodeEnvs[nodeID]
+	if !environments.Supports(l.managers[env], environments.CapabilityExec) {
+		return nil, fmt.Errorf("%s environment does not support exec and file copy", env)
+	}
+
+	operator, ok := nodeInstance.(node.Operator)
+	if !ok {
+		return nil, fmt.Errorf("node %s does not support exec and file copy", nodeID)
+	}
+	return operator, nil
+}
+
+// startNode starts a node of the running network with fresh enodes
+func (l *Launcher) startNode(nodeCfg node.Config) error {
+	// Make sure the node's environment is available
+	if err := l.initManagers(); err != nil {
+		return err
+	}
+
+	// Build Thor binary if needed
+	if err := l.buildThorBinaryIfNeeded(); err != nil {
+		return fmt.Errorf("failed to build thor binary: %w", err)
+	}
 
-	switch l.networkCfg.Environment {
-	case environments.Local:
-		execPath, err = l.localManager.BuildThorBinary(l.networkCfg.ThorBuilder)
-	case environments.Docker:
-		execPath, err = l.dockerManager.BuildThorBinary(l.networkCfg.ThorBuilder)
-	default:
-		return fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
+	// Validate the node before starting
+	if err := l.validateNode(nodeCfg); err != nil {
+		return fmt.Errorf("failed to validate node %s: %w", nodeCfg.GetID(), err)
 	}
 
+	// Generate fresh enodes including the node
+	enodes, err := l.generateEnodes()
+	if err != nil {
+		return fmt.Errorf("failed to generate enodes: %w", err)
+	}
+
+	// Start the node using the manager of its environment
+	env := l.environmentOf(nodeCfg)
+	bootnodes, err := l.peerEnodes(nodeCfg, enodes[env])
 	if err != nil {
 		return err
 	}
+	nodeInstance, err := l.managers[env].StartNode(nodeCfg, l.networkCfg, bootnodes)
+	if err != nil {
+		return err
+	}
+
+	l.nodes[nodeCfg.GetID()] = nodeInstance
+	l.nodeEnvs[nodeCfg.GetID()] = env
+	return nil
+}
+
+// pauser returns the running node instance as a node.Pauser
+func (l *Launcher) pauser(nodeID string) (node.Pauser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	nodeInstance, exists := l.nodes[nodeID]
+	if !exists {
+		return nil, fmt.Errorf("node with ID %s is not running", nodeID)
+	}
+
+	env := l.nodeEnvs[nodeID]
+	pauser, ok := nodeInstance.(node.Pauser)
+	if !ok || !environments.Supports(l.managers[env], environments.CapabilityPause) {
+		return nil, fmt.Errorf("%s environment does not support pausing nodes", env)
+	}
+	return pauser, nil
+}
+
+// partitioner returns the manager that partitions the running network
+func (l *Launcher) partitioner() (environments.Partitioner, error) {
+	manager, env, err := l.networkManager("partitions")
+	if err != nil {
+		return nil, err
+	}
 
-	// Set exec artifact for nodes that don't have one configured
-	if execPath != "" {
-		for _, nodeConfig := range l.networkCfg.Nodes {
-			if nodeConfig.GetExecArtifact() == "" {
-				nodeConfig.SetExecArtifact(execPath)
+	partitioner, ok := manager.(environments.Partitioner)
+	if !ok || !environments.Supports(manager, environments.CapabilityPartition) {
+		return nil, fmt.Errorf("%s environment does not support partitions", env)
+	}
+	return partitioner, nil
+}
+
+// linkShaper returns the manager that shapes the links of the running network
+func (l *Launcher) linkShaper() (environments.LinkShaper, error) {
+	manager, env, err := l.networkManager("link shaping")
+	if err != nil {
+		return nil, err
+	}
+
+	shaper, ok := manager.(environments.LinkShaper)
+	if !ok || !environments.Supports(manager, environments.CapabilityLinkShaping) {
+		return nil, fmt.Errorf("%s environment does not support link shaping", env)
+	}
+	return shaper, nil
+}
+
+// networkManager returns the manager running every node, for operations spanning the whole network
+func (l *Launcher) networkManager(operation string) (environments.Manager, string, error) {
+	if !l.started {
+		return nil, "", fmt.Errorf("network is not running")
+	}
+
+	envs := l.environmentsInUse()
+	if len(envs) != 1 {
+		return nil, "", fmt.Errorf("%s need all nodes in a single environment, got %v", operation, envs)
+	}
+	return l.managers[envs[0]], envs[0], nil
+}
+
+// initManagers creates the managers of every environment in use and checks they can run the network
+func (l *Launcher) initManagers() error {
+	envs := l.environmentsInUse()
+	for _, env := range envs {
+		if _, exists := l.managers[env]; !exists {
+			manager, err := environments.NewManager(env, l.networkCfg)
+			if err != nil {
+				return fmt.Errorf("failed to initialize %s environment: %w", env, err)
+			}
+			l.managers[env] = manager
+		}
+
+		manager := l.managers[env]
+		if l.networkCfg.IsPublicNetwork() && !environments.Supports(manager, environments.CapabilityPublicNetworks) {
+			return fmt.Errorf("%s environment does not support public networks", env)
+		}
+		if len(envs) > 1 {
+			if _, ok := manager.(environments.HostBridge); !ok || !environments.Supports(manager, environments.CapabilityMixedNetworks) {
+				return fmt.Errorf("%s environment cannot peer with nodes of other environments", env)
+			}
+		}
+	}
+	return nil
+}
+
+// environmentOf returns the environment a node runs in
+func (l *Launcher) environmentOf(nodeCfg node.Config) string {
+	if env := nodeCfg.GetEnvironment(); env != "" {
+		return env
+	}
+	return l.networkCfg.Environment
+}
+
+// environmentsInUse returns the environments of the network's nodes in order of first use
+func (l *Launcher) environmentsInUse() []string {
+	var envs []string
+	for _, nodeCfg := range l.networkCfg.Nodes {
+		if env := l.environmentOf(nodeCfg); !slices.Contains(envs, env) {
+			envs = append(envs, env)
+		}
+	}
+	if len(envs) == 0 {
+		envs = append(envs, l.networkCfg.Environment)
+	}
+	return envs
+}
+
+// nodesIn returns the network's nodes running in the given environment
+func (l *Launcher) nodesIn(env string) []node.Config {
+	var nodes []node.Config
+	for _, nodeCfg := range l.networkCfg.Nodes {
+		if l.environmentOf(nodeCfg) == env {
+			nodes = append(nodes, nodeCfg)
+		}
+	}
+	return nodes
+}
+
+// buildThorBinaryIfNeeded builds the thor artifact of each environment in use that runs one and sets it on its nodes
+func (l *Launcher) buildThorBinaryIfNeeded() error {
+	for _, env := range l.environmentsInUse() {
+		if !environments.Supports(l.managers[env], environments.CapabilityThorBuild) {
+			continue
+		}
+		execPath, err := l.managers[env].BuildThorBinary(l.networkCfg.ThorBuilder)
+		if err != nil {
+			return err
+		}
+
+		// Set exec artifact for nodes that don't have one configured
+		if execPath != "" {
+			for _, nodeConfig := range l.nodesIn(env) {
+				if nodeConfig.GetExecArtifact() == "" {
+					nodeConfig.SetExecArtifact(execPath)
+				}
 			}
 		}
 	}
@@ -285,38 +624,162 @@ func (l *Launcher) buildThorBinaryIfNeeded() error {
 	return nil
 }
 
-// validateNode validates a node configuration
+// validateNode validates a node configuration and allocates its metrics address
 func (l *Launcher) validateNode(nodeCfg node.Config) error {
-	switch l.networkCfg.Environment {
-	case environments.Local:
-		return l.localManager.ValidateNode(nodeCfg)
-	case environments.Docker:
-		return l.dockerManager.ValidateNode(nodeCfg)
-	default:
-		return fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
-	}
-}
-
-// generateEnodes generates enode strings for all nodes
-func (l *Launcher) generateEnodes() ([]string, error) {
-	switch l.networkCfg.Environment {
-	case environments.Local:
-		return l.localManager.GenerateEnodes(l.networkCfg)
-	case environments.Docker:
-		return l.dockerManager.GenerateEnodes(l.networkCfg)
-	default:
-		return nil, fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
-	}
-}
-
-// stopNode stops a node instance
-func (l *Launcher) stopNode(nodeInstance node.Lifecycle) error {
-	switch l.networkCfg.Environment {
-	case environments.Local:
-		return l.localManager.StopNode(nodeInstance)
-	case environments.Docker:
-		return l.dockerManager.StopNode(nodeInstance)
-	default:
-		return fmt.Errorf("unsupported environment: %s", l.networkCfg.Environment)
+	env := l.environmentOf(nodeCfg)
+	if err := l.managers[env].ValidateNode(nodeCfg); err != nil {
+		return err
+	}
+
+	// A skew the environment cannot apply would leave the node on the host clock without notice
+	if skew := nodeCfg.GetClockSkew(); skew != nil {
+		if !environments.Supports(l.managers[env], environments.CapabilityClockSkew) {
+			return fmt.Errorf("%s environment cannot skew the clock of node %s", env, nodeCfg.GetID())
+		}
+		// Thor reads the time through the vDSO, only a thor built with a skewable clock can be skewed
+		if environments.Supports(l.managers[env], environments.CapabilityThorBuild) {
+			if builder := l.networkCfg.ThorBuilder; builder == nil || builder.BuildConfig == nil || !builder.BuildConfig.SkewableClock {
+				return fmt.Errorf("node %s has a clock skew, which needs thor built with BuildConfig.SkewableClock", nodeCfg.GetID())
+			}
+		}
+		if err := skew.Validate(); err != nil {
+			return fmt.Errorf("invalid clock skew for node %s: %w", nodeCfg.GetID(), err)
+		}
+	}
+
+	if !nodeCfg.IsMetricsEnabled() {
+		return nil
+	}
+	if !environments.Supports(l.managers[env], environments.CapabilityMetrics) {
+		return fmt.Errorf("%s environment does not serve node metrics", env)
+	}
+	if nodeCfg.GetMetricsAddr() == "" {
+		addr, err := freeAddr()
+		if err != nil {
+			return fmt.Errorf("unable to allocate metrics port: %w", err)
+		}
+		nodeCfg.SetMetricsAddr(addr)
+	}
+	return nil
+}
+
+// generateEnodes returns the bootnodes of each environment in use, as reachable from that environment.
+// Nodes of the same environment use the manager's own addressing, nodes of other environments are
+// reached through the host.
+func (l *Launcher) generateEnodes() (map[string][]string, error) {
+	envs := l.environmentsInUse()
+	if len(envs) == 1 {
+		enodes, err := l.managers[envs[0]].GenerateEnodes(l.networkCfg)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]string{envs[0]: enodes}, nil
+	}
+
+	own := make(map[string][]string)
+	viaHost := make(map[string][]string)
+	for _, env := range envs {
+		envNetwork := &network.Network{
+			Environment:      l.networkCfg.Environment,
+			Nodes:            l.nodesIn(env),
+			BaseID:           l.networkCfg.BaseID,
+			ThorBuilder:      l.networkCfg.ThorBuilder,
+			Topology:         l.networkCfg.Topology,
+			SkipGenesisCheck: l.networkCfg.SkipGenesisCheck,
+		}
+
+		enodes, err := l.managers[env].GenerateEnodes(envNetwork)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s enodes: %w", env, err)
+		}
+		own[env] = enodes
+
+		if l.networkCfg.IsPublicNetwork() {
+			continue
+		}
+		bridge := l.managers[env].(environments.HostBridge)
+		for _, nodeCfg := range envNetwork.Nodes {
+			enode, err := bridge.HostEnode(nodeCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate host enode for node %s: %w", nodeCfg.GetID(), err)
+			}
+			viaHost[env] = append(viaHost[env], enode)
+		}
+	}
+
+	enodes := make(map[string][]string)
+	for _, env := range envs {
+		hostIP := l.managers[env].(environments.HostBridge).HostIP()
+		enodes[env] = append(enodes[env], own[env]...)
+		for _, other := range envs {
+			if other == env {
+				continue
+			}
+			for _, enode := range viaHost[other] {
+				reachable, err := replaceEnodeHost(enode, hostIP)
+				if err != nil {
+					return nil, err
+				}
+				enodes[env] = append(enodes[env], reachable)
+			}
+		}
+	}
+	return enodes, nil
+}
+
+// peerEnodes narrows the bootnodes of a node down to the peers its topology expects.
+// Without a topology every node bootstraps from all enodes.
+func (l *Launcher) peerEnodes(nodeCfg node.Config, enodes []string) ([]string, error) {
+	if l.networkCfg.Topology == nil {
+		return enodes, nil
+	}
+
+	expectedPeers, err := l.networkCfg.ExpectedPeers()
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	peerIDs := make(map[string]bool)
+	for _, peerCfg := range l.networkCfg.Nodes {
+		if slices.Contains(expectedPeers[nodeCfg.GetID()], peerCfg.GetID()) {
+			peerIDs[peerCfg.PeerID()] = true
+		}
+	}
+
+	var peerEnodes []string
+	for _, enode := range enodes {
+		u, err := url.Parse(enode)
+		if err != nil || u.User == nil {
+			return nil, fmt.Errorf("invalid enode %s", enode)
+		}
+		if peerIDs[u.User.Username()] {
+			peerEnodes = append(peerEnodes, enode)
+		}
+	}
+	return peerEnodes, nil
+}
+
+// stopNode stops a node instance with the manager it was started by
+func (l *Launcher) stopNode(nodeID string, nodeInstance node.Lifecycle) error {
+	return l.managers[l.nodeEnvs[nodeID]].StopNode(nodeInstance)
+}
+
+// freeAddr returns a loopback address with a TCP port that is free at the time of the call
+func freeAddr() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer listener.Close()
+	return listener.Addr().String(), nil
+}
+
+// replaceEnodeHost points an enode at another host address, keeping its node ID and port
+func replaceEnodeHost(enode, host string) (string, error) {
+	u, err := url.Parse(enode)
+	if err != nil || u.Scheme != "enode" || u.User == nil {
+		return "", fmt.Errorf("invalid enode %s", enode)
 	}
+	u.Host = net.JoinHostPort(host, u.Port())
+	return u.String(), nil
 }
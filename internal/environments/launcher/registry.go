@@ -0,0 +1,40 @@
+package launcher
+
+import (
+	"fmt"
+
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/internal/environments/docker"
+	"github.com/vechain/networkhub/internal/environments/fake"
+	"github.com/vechain/networkhub/internal/environments/inprocess"
+	"github.com/vechain/networkhub/internal/environments/local"
+	"github.com/vechain/networkhub/network"
+)
+
+// builtinEnvironments are registered for every program using the launcher
+var builtinEnvironments = map[string]environments.Factory{
+	environments.Local: func(_ *network.Network) (environments.Manager, error) {
+		return local.NewManager(), nil
+	},
+	environments.Docker: func(cfg *network.Network) (environments.Manager, error) {
+		manager := docker.NewManager()
+		if err := manager.Initialize(cfg); err != nil {
+			return nil, fmt.Errorf("failed to initialize Docker manager: %w", err)
+		}
+		return manager, nil
+	},
+	environments.InProcess: func(_ *network.Network) (environments.Manager, error) {
+		return inprocess.NewManager(), nil
+	},
+	environments.Fake: func(_ *network.Network) (environments.Manager, error) {
+		return fake.NewManager(), nil
+	},
+}
+
+func init() {
+	for name, factory := range builtinEnvironments {
+		if err := environments.Register(name, factory); err != nil {
+			panic(err)
+		}
+	}
+}
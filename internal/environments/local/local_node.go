@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -15,6 +16,7 @@ import (
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
 	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/networkhub/thorbuilder"
 )
 
 type Node struct {
@@ -22,6 +24,10 @@ type Node struct {
 	networkCfg *network.Network
 	cmdExec    *exec.Cmd
 	enodes     []string
+	args       []string
+	logFile    *os.File
+	// keepData is set once the node started, so starting it again resumes its chain instead of wiping it
+	keepData bool
 }
 
 func NewLocalNode(nodeCfg node.Config, networkCfg *network.Network, enodes []string) *Node {
@@ -73,10 +79,37 @@ func (n *Node) Start() error {
 		return fmt.Errorf("failed to create command: %w", err)
 	}
 
-	return n.executeCommand(cmd)
+	if err := n.executeCommand(cmd); err != nil {
+		return err
+	}
+	n.keepData = true
+	return nil
+}
+
+// Kill kills the thor process without waiting for it to shut down, as a crash would
+func (n *Node) Kill() error {
+	defer n.closeLog()
+
+	if n.cmdExec == nil {
+		return nil
+	}
+	if err := n.cmdExec.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill process - %w", err)
+	}
+	_ = n.cmdExec.Wait()
+	n.cmdExec = nil
+	return nil
 }
 
 func (n *Node) Stop() error {
+	defer n.closeLog()
+
+	// Nothing to stop if the process was never started (e.g. FakeExecution) or was killed
+	if n.cmdExec == nil {
+		return nil
+	}
+	defer func() { n.cmdExec = nil }()
+
 	// Send an interrupt signal
 	if err := n.cmdExec.Process.Signal(os.Interrupt); err != nil {
 		return fmt.Errorf("failed to send interrupt signal - %w", err)
@@ -94,7 +127,7 @@ func (n *Node) Stop() error {
 
 	select {
 	case <-ctx.Done():
-		if err := n.cmdExec.Process.Kill(); err != nil {
+		if err := n.cmdExec.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
 			slog.Warn("failed to kill node", "id", n.nodeCfg.GetID(), "pid", n.cmdExec.Process.Pid)
 		} else {
 			slog.Warn("process killed as timeout reached", "id", n.nodeCfg.GetID(), "pid", n.cmdExec.Process.Pid)
@@ -108,6 +141,36 @@ func (n *Node) Stop() error {
 	return nil
 }
 
+// CommandLine returns the thor command line the node was started with
+func (n *Node) CommandLine() []string {
+	return n.args
+}
+
+// Logs returns the output of every start of the node, each one headed by a start line
+func (n *Node) Logs() (io.ReadCloser, error) {
+	logFile, err := os.Open(n.logPath())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open logs of node %s: %w", n.nodeCfg.GetID(), err)
+	}
+	return logFile, nil
+}
+
+// logPath returns the file the node's output is written to, next to its keys and genesis
+func (n *Node) logPath() string {
+	return filepath.Join(n.nodeCfg.GetConfigDir(), "thor.log")
+}
+
+// closeLog closes the node's log file, if open
+func (n *Node) closeLog() {
+	if n.logFile == nil {
+		return
+	}
+	if err := n.logFile.Close(); err != nil {
+		slog.Warn("failed to close node log", "id", n.nodeCfg.GetID(), "error", err)
+	}
+	n.logFile = nil
+}
+
 type nodeWriter struct {
 	id string
 	w  io.Writer
@@ -220,7 +283,7 @@ func (n *Node) writeGenesis(isPublicNetwork bool) error {
 	}
 
 	genesisPath := filepath.Join(n.nodeCfg.GetConfigDir(), "genesis.json")
-	genesisBytes, err := nodegenesis.Marshal(n.nodeCfg.GetGenesis())
+	genesisBytes, err := nodegenesis.MarshalFor(n.nodeCfg.GetGenesis(), n.nodeCfg.GetThorVersion())
 	if err != nil {
 		return fmt.Errorf("unable to marshal genesis: %w", err)
 	}
@@ -234,7 +297,7 @@ func (n *Node) writeGenesis(isPublicNetwork bool) error {
 
 // cleanDataDirectory removes the data directory for local networks
 func (n *Node) cleanDataDirectory(isPublicNetwork bool) error {
-	if isPublicNetwork || n.nodeCfg.IsPersistent() {
+	if isPublicNetwork || n.nodeCfg.IsPersistent() || n.keepData {
 		return nil // Public networks should sync from scratch, don't clean
 	}
 
@@ -295,10 +358,13 @@ func (n *Node) addCommonArgs(args []string) []string {
 		"--nat", "none",
 		"--p2p-port", fmt.Sprintf("%d", n.nodeCfg.GetP2PListenPort()),
 	)
+	if n.nodeCfg.IsMetricsEnabled() {
+		args = append(args, "--enable-metrics", "--metrics-addr", n.nodeCfg.GetMetricsAddr())
+	}
 	return args
 }
 
-// addBootnodes adds bootnode arguments for local networks
+// addBootnodes adds bootnode arguments for local networks, or allowed peers when the network declares a topology
 func (n *Node) addBootnodes(args []string) ([]string, error) {
 	isPublicNetwork := n.isPublicNetwork()
 	if isPublicNetwork {
@@ -308,7 +374,12 @@ func (n *Node) addBootnodes(args []string) ([]string, error) {
 	cleanEnodes := n.cleanEnodes()
 	if len(cleanEnodes) > 0 {
 		enodeString := strings.Join(cleanEnodes, ",")
-		args = append(args, "--bootnode", enodeString)
+		// A declared topology pins the peers, bootnodes would let discovery connect everyone
+		if n.networkCfg.Topology != nil {
+			args = append(args, "--allowed-peers", enodeString)
+		} else {
+			args = append(args, "--bootnode", enodeString)
+		}
 	}
 
 	return args, nil
@@ -338,19 +409,37 @@ func (n *Node) addAdditionalArgs(args []string) []string {
 	return args
 }
 
-// createCommand creates the exec.Cmd with the given arguments
+// createCommand creates the exec.Cmd with the given arguments, also appending its output to the node's log file.
+// The log is kept across starts, so the output of a crashed run survives the restart.
 func (n *Node) createCommand(args []string) (*exec.Cmd, error) {
+	n.closeLog()
+	logFile, err := os.OpenFile(n.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	if _, err := fmt.Fprintf(logFile, "==> starting node %s at %s\n", n.nodeCfg.GetID(), time.Now().Format(time.RFC3339)); err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to write log file: %w", err)
+	}
+	n.logFile = logFile
+	n.args = args
+
 	cmd := &exec.Cmd{
 		Path: n.nodeCfg.GetExecArtifact(),
 		Args: args,
-		Stdout: &nodeWriter{
+		Stdout: io.MultiWriter(logFile, &nodeWriter{
 			id: n.nodeCfg.GetID(),
 			w:  os.Stdout,
-		},
-		Stderr: &nodeWriter{
+		}),
+		Stderr: io.MultiWriter(logFile, &nodeWriter{
 			id: n.nodeCfg.GetID(),
 			w:  os.Stderr,
-		},
+		}),
+	}
+
+	// Skew the clock of a thor built with a skewable clock
+	if skew := n.nodeCfg.GetClockSkew(); skew != nil {
+		cmd.Env = append(os.Environ(), thorbuilder.ClockSkewEnv(skew.Offset, skew.Drift)...)
 	}
 
 	return cmd, nil
@@ -369,6 +458,7 @@ func (n *Node) executeCommand(cmd *exec.Cmd) error {
 
 	// Start the command and check for errors
 	if err := cmd.Start(); err != nil {
+		n.closeLog()
 		return fmt.Errorf("failed to start thor command: %w", err)
 	}
 
@@ -0,0 +1,190 @@
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/vechain/networkhub/network/node"
+)
+
+// Exec runs argv as a local process in the node's data directory
+func (n *Node) Exec(argv []string) (*node.ExecResult, error) {
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("command cannot be empty")
+	}
+
+	name := argv[0]
+	if name == "thor" {
+		name = n.nodeCfg.GetExecArtifact()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, argv[1:]...)
+	cmd.Dir = n.nodeCfg.GetDataDir()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := &node.ExecResult{}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("failed to run command on node %s: %w", n.nodeCfg.GetID(), err)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	return result, nil
+}
+
+// CopyFrom returns a tar archive of the file or directory at path
+func (n *Node) CopyFrom(path string) (io.ReadCloser, error) {
+	src := n.resolvePath(path)
+	if _, err := os.Lstat(src); err != nil {
+		return nil, fmt.Errorf("unable to copy from node %s: %w", n.nodeCfg.GetID(), err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, src))
+	}()
+	return pr, nil
+}
+
+// CopyTo extracts the tar archive into the directory at path
+func (n *Node) CopyTo(path string, archive io.Reader) error {
+	dst := n.resolvePath(path)
+	info, err := os.Stat(dst)
+	if err != nil {
+		return fmt.Errorf("unable to copy to node %s: %w", n.nodeCfg.GetID(), err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("unable to copy to node %s: %s is not a directory", n.nodeCfg.GetID(), dst)
+	}
+
+	return extractTar(archive, dst)
+}
+
+// resolvePath resolves relative paths against the node's data directory
+func (n *Node) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(n.nodeCfg.GetDataDir(), path)
+}
+
+// writeTar writes src as a tar archive whose entries are named relative to the parent of src
+func writeTar(w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Dir(src)
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// extractTar extracts a tar archive into dst, rejecting entries that escape it
+func extractTar(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(header.Name))
+		if target != dst && !strings.HasPrefix(target, dst+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %s escapes destination", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported archive entry type %c for %s", header.Typeflag, header.Name)
+		}
+	}
+}
+
+// writeFile writes the content of r to a new file at path
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
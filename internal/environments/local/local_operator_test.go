@@ -0,0 +1,68 @@
+package local_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments/local"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/preset"
+)
+
+func TestLocalNodeOperator(t *testing.T) {
+	dataDir := t.TempDir()
+	nodeCfg := &node.BaseNode{
+		ID:      "node1",
+		DataDir: dataDir,
+	}
+	localNode := local.NewLocalNode(nodeCfg, preset.LocalThreeNodesNetwork(), nil)
+
+	t.Run("Exec", func(t *testing.T) {
+		result, err := localNode.Exec([]string{"sh", "-c", "pwd; echo oops >&2; exit 3"})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "oops\n", result.Stderr)
+
+		resolved, err := filepath.EvalSymlinks(dataDir)
+		require.NoError(t, err)
+		assert.Equal(t, resolved+"\n", result.Stdout)
+	})
+
+	t.Run("Exec empty command", func(t *testing.T) {
+		_, err := localNode.Exec(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("CopyFrom and CopyTo", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "src", "nested"), 0777))
+		require.NoError(t, os.WriteFile(filepath.Join(dataDir, "src", "nested", "file.txt"), []byte("hello"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "dst"), 0777))
+
+		archive, err := localNode.CopyFrom("src")
+		require.NoError(t, err)
+		defer archive.Close()
+
+		require.NoError(t, localNode.CopyTo("dst", archive))
+
+		content, err := os.ReadFile(filepath.Join(dataDir, "dst", "src", "nested", "file.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(content))
+	})
+
+	t.Run("CopyFrom missing path", func(t *testing.T) {
+		_, err := localNode.CopyFrom("missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("CopyTo file destination", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dataDir, "plain"), []byte("x"), 0644))
+		archive, err := localNode.CopyFrom("plain")
+		require.NoError(t, err)
+		defer archive.Close()
+
+		assert.Error(t, localNode.CopyTo("plain", archive))
+	})
+}
@@ -1,11 +1,19 @@
 package local_test
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/vechain/networkhub/internal/environments/launcher"
+	"github.com/vechain/networkhub/internal/environments/local"
+	"github.com/vechain/networkhub/network/node"
 	"github.com/vechain/networkhub/preset"
+	"github.com/vechain/networkhub/thorbuilder"
 )
 
 func TestLocalInvalidExecArtifact(t *testing.T) {
@@ -22,3 +30,60 @@ func TestLocalInvalidExecArtifact(t *testing.T) {
 
 	require.ErrorContains(t, err, "artifact path /some_fake_dir does not exist for node")
 }
+
+func TestLocalStopWithoutProcess(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	localNode := local.NewLocalNode(networkCfg.Nodes[0], networkCfg, nil)
+
+	require.NoError(t, localNode.Stop())
+}
+
+func TestLocalLogsSurviveRestart(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	nodeCfg := networkCfg.Nodes[0]
+	dir := t.TempDir()
+	thorPath := filepath.Join(dir, "thor")
+	require.NoError(t, os.WriteFile(thorPath, []byte("#!/bin/sh\necho thor running\nexec sleep 30\n"), 0755))
+	nodeCfg.SetExecArtifact(thorPath)
+	nodeCfg.SetConfigDir(filepath.Join(dir, "config"))
+	nodeCfg.SetDataDir(filepath.Join(dir, "data"))
+
+	localNode := local.NewLocalNode(nodeCfg, networkCfg, nil)
+	require.NoError(t, localNode.Start())
+	require.Eventually(t, func() bool { return strings.Contains(readLogs(t, localNode), "thor running") }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, localNode.Kill())
+
+	// The output of the killed run is still there after the restart
+	require.NoError(t, localNode.Start())
+	t.Cleanup(func() { require.NoError(t, localNode.Kill()) })
+	require.Eventually(t, func() bool { return strings.Count(readLogs(t, localNode), "thor running") == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 2, strings.Count(readLogs(t, localNode), "==> starting node node1"))
+}
+
+func TestLocalClockSkew(t *testing.T) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	nodeCfg := networkCfg.Nodes[0]
+	dir := t.TempDir()
+	thorPath := filepath.Join(dir, "thor")
+	script := "#!/bin/sh\necho clock $" + thorbuilder.ClockOffsetEnv + " $" + thorbuilder.ClockDriftEnv + "\nexec sleep 30\n"
+	require.NoError(t, os.WriteFile(thorPath, []byte(script), 0755))
+	nodeCfg.SetExecArtifact(thorPath)
+	nodeCfg.SetConfigDir(filepath.Join(dir, "config"))
+	nodeCfg.SetDataDir(filepath.Join(dir, "data"))
+	nodeCfg.SetClockSkew(&node.ClockSkew{Offset: -1500 * time.Millisecond, Drift: 0.001})
+
+	// The skew reaches thor through the environment of its process
+	localNode := local.NewLocalNode(nodeCfg, networkCfg, nil)
+	require.NoError(t, localNode.Start())
+	t.Cleanup(func() { require.NoError(t, localNode.Kill()) })
+	require.Eventually(t, func() bool { return strings.Contains(readLogs(t, localNode), "clock -1.5s 0.001") }, 5*time.Second, 10*time.Millisecond)
+}
+
+func readLogs(t *testing.T, localNode *local.Node) string {
+	logs, err := localNode.Logs()
+	require.NoError(t, err)
+	defer logs.Close()
+	data, err := io.ReadAll(logs)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/vechain/networkhub/internal/environments"
 	"github.com/vechain/networkhub/network"
 	"github.com/vechain/networkhub/network/node"
 	"github.com/vechain/networkhub/thorbuilder"
@@ -39,6 +40,20 @@ func (m *Manager) StopNode(nodeInstance node.Lifecycle) error {
 	return nodeInstance.Stop()
 }
 
+// KillNode kills a local node process, keeping its data dir for RestartNode
+func (m *Manager) KillNode(nodeInstance node.Lifecycle) error {
+	localNode, ok := nodeInstance.(*Node)
+	if !ok {
+		return fmt.Errorf("not a local node")
+	}
+	return localNode.Kill()
+}
+
+// RestartNode starts a killed local node again on its existing data dir
+func (m *Manager) RestartNode(nodeInstance node.Lifecycle) error {
+	return nodeInstance.Start()
+}
+
 // BuildThorBinary builds the thor binary if needed and returns the path
 func (m *Manager) BuildThorBinary(thorBuilder *thorbuilder.Config) (string, error) {
 	if thorBuilder == nil {
@@ -97,6 +112,33 @@ func (m *Manager) GenerateEnodes(networkCfg *network.Network) ([]string, error)
 	return enodes, nil
 }
 
+// Cleanup is a no-op as local nodes leave nothing behind once stopped
+func (m *Manager) Cleanup() error {
+	return nil
+}
+
+// Capabilities lists the optional features of the local environment
+func (m *Manager) Capabilities() []environments.Capability {
+	return []environments.Capability{
+		environments.CapabilityExec,
+		environments.CapabilityPublicNetworks,
+		environments.CapabilityThorBuild,
+		environments.CapabilityMixedNetworks,
+		environments.CapabilityMetrics,
+		environments.CapabilityClockSkew,
+	}
+}
+
+// HostEnode returns the enode of a local node, which listens on the host itself
+func (m *Manager) HostEnode(nodeCfg node.Config) (string, error) {
+	return nodeCfg.Enode("127.0.0.1")
+}
+
+// HostIP returns the loopback address, local nodes run on the host
+func (m *Manager) HostIP() string {
+	return "127.0.0.1"
+}
+
 // Helper functions
 
 // fileExists checks if a file exists
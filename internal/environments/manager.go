@@ -0,0 +1,106 @@
+package environments
+
+import (
+	"slices"
+
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/thorbuilder"
+)
+
+// Manager runs nodes for one environment; the launcher drives every environment through it
+type Manager interface {
+	// ValidateNode validates and sets defaults for a node configuration
+	ValidateNode(nodeCfg node.Config) error
+
+	// BuildThorBinary builds the thor artifact and returns the exec path to set on nodes, if any.
+	// The launcher only calls it on environments listing CapabilityThorBuild.
+	BuildThorBinary(thorBuilder *thorbuilder.Config) (string, error)
+
+	// GenerateEnodes creates the bootnode list for all nodes in the network
+	GenerateEnodes(networkCfg *network.Network) ([]string, error)
+
+	// StartNode starts a node and returns its running instance
+	StartNode(nodeCfg node.Config, networkCfg *network.Network, enodes []string) (node.Lifecycle, error)
+
+	// StopNode stops a running node instance
+	StopNode(nodeInstance node.Lifecycle) error
+
+	// Cleanup releases environment resources once all nodes are stopped
+	Cleanup() error
+
+	// Capabilities lists the optional features the environment supports
+	Capabilities() []Capability
+}
+
+// Capability names an optional feature an environment may support
+type Capability string
+
+const (
+	// CapabilityExec means running nodes implement node.Operator (exec and file copy)
+	CapabilityExec Capability = "exec"
+
+	// CapabilityPublicNetworks means nodes can join mainnet or testnet
+	CapabilityPublicNetworks Capability = "public-networks"
+
+	// CapabilityThorBuild means the environment runs a thor artifact built by thorbuilder
+	CapabilityThorBuild Capability = "thor-build"
+
+	// CapabilityMixedNetworks means nodes can peer with nodes of other environments, the manager implements HostBridge
+	CapabilityMixedNetworks Capability = "mixed-networks"
+
+	// CapabilityPartition means the manager implements Partitioner
+	CapabilityPartition Capability = "partition"
+
+	// CapabilityLinkShaping means the manager implements LinkShaper
+	CapabilityLinkShaping Capability = "link-shaping"
+
+	// CapabilityPause means running nodes implement node.Pauser
+	CapabilityPause Capability = "pause"
+
+	// CapabilityClockSkew means the environment applies the clock skew of nodes
+	CapabilityClockSkew Capability = "clock-skew"
+
+	// CapabilityMetrics means nodes with metrics enabled serve thor's Prometheus metrics on their metrics address
+	CapabilityMetrics Capability = "metrics"
+)
+
+// HostBridge is implemented by managers whose nodes can peer with other environments through the host.
+// An enode from one environment reaches another by swapping its host address for the dialer's HostIP.
+type HostBridge interface {
+	// HostEnode returns the enode that reaches the node from the host
+	HostEnode(nodeCfg node.Config) (string, error)
+
+	// HostIP returns the address nodes of the environment use to reach the host
+	HostIP() string
+}
+
+// Partitioner is implemented by managers that can split running nodes into groups that cannot reach each other
+type Partitioner interface {
+	// Partition cuts all traffic between nodes of different groups, replacing any previous partition
+	Partition(groups ...[]string) error
+
+	// Heal restores traffic between all nodes
+	Heal() error
+}
+
+// Restarter is implemented by managers that can restart a stopped node with its chain data, as a crashed
+// node would. Without it a stopped node starts again as a new node.
+type Restarter interface {
+	// KillNode stops a running node abruptly, keeping what it needs to restart
+	KillNode(nodeInstance node.Lifecycle) error
+
+	// RestartNode starts a killed node again with the data it had
+	RestartNode(nodeInstance node.Lifecycle) error
+}
+
+// LinkShaper is implemented by managers that can shape the traffic between running nodes
+type LinkShaper interface {
+	// ShapeLinks replaces the shaping of every running node, nodes missing from links are left unshaped
+	ShapeLinks(links network.Links) error
+}
+
+// Supports reports whether the manager lists the capability
+func Supports(manager Manager, capability Capability) bool {
+	return slices.Contains(manager.Capabilities(), capability)
+}
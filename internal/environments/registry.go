@@ -0,0 +1,69 @@
+package environments
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/vechain/networkhub/network"
+)
+
+// Factory creates the manager for a network configured with the environment
+type Factory func(networkCfg *network.Network) (Manager, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes an environment available under the given name
+func Register(name string, factory Factory) error {
+	if name == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("factory for environment %s cannot be nil", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("environment %s is already registered", name)
+	}
+	registry[name] = factory
+	return nil
+}
+
+// Unregister removes an environment, so tests can register theirs again
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, name)
+}
+
+// NewManager creates the manager of the named environment for the network
+func NewManager(name string, networkCfg *network.Network) (Manager, error) {
+	registryMu.RLock()
+	factory, exists := registry[name]
+	registryMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unsupported environment: %s", name)
+	}
+	return factory(networkCfg)
+}
+
+// Registered returns the names of all registered environments, sorted
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,99 @@
+// Package metrics exposes networkhub's own Prometheus metrics: node restarts, health-check latency,
+// thor build durations and the block height of each node. They are kept in a dedicated registry,
+// served by Handler, so they don't mix with the metrics of the process embedding networkhub.
+package metrics
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Build kinds reported by ObserveBuild
+const (
+	BuildBinary        = "binary"
+	BuildDockerImage   = "docker-image"
+	BuildDockerPackage = "docker-package"
+)
+
+var (
+	// Registry holds every networkhub metric
+	Registry = prometheus.NewRegistry()
+
+	nodeRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "networkhub_node_restarts_total",
+		Help: "Number of times a stopped node was started again.",
+	}, []string{"node"})
+
+	healthCheckDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "networkhub_health_check_duration_seconds",
+		Help:    "Time a network health check took until the network was healthy or the check timed out.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"healthy"})
+
+	buildDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "networkhub_build_duration_seconds",
+		Help:    "Time taken to compile thor binaries, and to build or reuse Docker images.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"kind"})
+
+	blockHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "networkhub_block_height",
+		Help: "Best block number last seen on each node.",
+	}, []string{"node"})
+)
+
+func init() {
+	Registry.MustRegister(nodeRestarts, healthCheckDuration, buildDuration, blockHeight)
+}
+
+// NodeRestarted counts a restart of the node
+func NodeRestarted(nodeID string) {
+	nodeRestarts.WithLabelValues(nodeID).Inc()
+}
+
+// ObserveHealthCheck records how long a health check took and whether the network was healthy
+func ObserveHealthCheck(duration time.Duration, healthy bool) {
+	healthCheckDuration.WithLabelValues(fmt.Sprint(healthy)).Observe(duration.Seconds())
+}
+
+// ObserveBuild records the time since a build of the given kind started, meant to be deferred
+func ObserveBuild(kind string, start time.Time) {
+	buildDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
+
+// SetBlockHeight records the best block seen on a node
+func SetBlockHeight(nodeID string, number uint32) {
+	blockHeight.WithLabelValues(nodeID).Set(float64(number))
+}
+
+// Handler serves the networkhub metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serve serves the metrics on addr under /metrics until the server is closed.
+// An addr with port 0 listens on an ephemeral port, the returned server holds the actual address.
+func Serve(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: listener.Addr().String(), Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server stopped", "addr", server.Addr, "error", err)
+		}
+	}()
+	slog.Info("serving networkhub metrics", "addr", server.Addr)
+	return server, nil
+}
@@ -0,0 +1,431 @@
+// Package monitor watches a running network in the background and raises alerts when the chain stalls,
+// a node falls behind, peers drop or nodes disagree on a block, so long-running tests can fail fast.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vechain/networkhub/metrics"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/thor/v2/thorclient"
+)
+
+// defaultBlockInterval is thor's block interval, used when the genesis does not configure one
+const defaultBlockInterval = 10 * time.Second
+
+// Kind is the condition an event reports
+type Kind string
+
+const (
+	// KindStall reports that no node produced a new best block for StallAfter
+	KindStall Kind = "stall"
+	// KindLagging reports a node more than MaxLag blocks behind the network's best block
+	KindLagging Kind = "lagging"
+	// KindPeersDropped reports a node with fewer peers than its topology expects
+	KindPeersDropped Kind = "peers-dropped"
+	// KindDivergence reports nodes that can reach each other holding different blocks at the same height
+	KindDivergence Kind = "divergence"
+	// KindUnreachable reports a node whose API does not answer
+	KindUnreachable Kind = "unreachable"
+)
+
+// Config configures the checks of a monitor, zero values use the defaults
+type Config struct {
+	Interval      time.Duration // time between polls, 1s by default
+	StallAfter    time.Duration // longest time without a new best block, three genesis block intervals by default
+	MaxLag        uint32        // most blocks a node may be behind the network's best block, 2 by default
+	Confirmations uint32        // depth below the lowest best block at which nodes must agree, 1 by default
+}
+
+// Event is raised when a condition starts, and again with Resolved set once it clears
+type Event struct {
+	Time     time.Time `json:"time"`
+	Kind     Kind      `json:"kind"`
+	Node     string    `json:"node,omitempty"` // empty for network wide conditions
+	Block    uint32    `json:"block"`
+	Message  string    `json:"message"`
+	Resolved bool      `json:"resolved,omitempty"`
+}
+
+func (e Event) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+// NodeStatus is the latest state of a node seen by the monitor
+type NodeStatus struct {
+	ID            string        `json:"id"`
+	Reachable     bool          `json:"reachable"`
+	Best          uint32        `json:"best"`
+	Finalized     uint32        `json:"finalized"`
+	PeerCount     int           `json:"peerCount"`
+	ExpectedPeers int           `json:"expectedPeers"`
+	BlockInterval time.Duration `json:"blockInterval"` // average interval between the node's best blocks since the monitor started
+}
+
+// Monitor polls every node of a network until stopped
+type Monitor struct {
+	cfg        Config
+	networkCfg *network.Network
+	clients    map[string]*thorclient.Client
+	first      map[string]baseline // first best block seen of each node, to measure the cadence
+	events     chan Event
+	cancel     context.CancelFunc
+	done       chan struct{}
+
+	mu       sync.Mutex
+	status   map[string]*NodeStatus
+	active   map[alertKey]Event
+	err      error
+	best     uint32
+	progress time.Time // last time the network's best block advanced
+}
+
+type alertKey struct {
+	kind Kind
+	node string
+}
+
+type baseline struct {
+	number    uint32
+	timestamp uint64
+}
+
+// Start monitors the network until ctx is done or Stop is called
+func Start(ctx context.Context, networkCfg *network.Network, cfg Config) (*Monitor, error) {
+	if len(networkCfg.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes defined in the network")
+	}
+	if _, err := networkCfg.ExpectedPeers(); err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	blockInterval := defaultBlockInterval
+	if gen := networkCfg.Nodes[0].GetGenesis(); gen != nil && gen.Config != nil && gen.Config.BlockInterval > 0 {
+		blockInterval = time.Duration(gen.Config.BlockInterval) * time.Second
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	if cfg.StallAfter <= 0 {
+		cfg.StallAfter = 3 * blockInterval
+	}
+	if cfg.MaxLag == 0 {
+		cfg.MaxLag = 2
+	}
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m := &Monitor{
+		cfg:        cfg,
+		networkCfg: networkCfg,
+		clients:    make(map[string]*thorclient.Client),
+		first:      make(map[string]baseline),
+		events:     make(chan Event, 64),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		status:     make(map[string]*NodeStatus),
+		active:     make(map[alertKey]Event),
+		progress:   time.Now(),
+	}
+	go m.run(ctx)
+	return m, nil
+}
+
+// Events returns the channel alerts and their resolutions are sent on, it is closed when the monitor stops.
+// Events are dropped while the channel is full.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Err returns the first alert raised since the monitor started, nil if the network has been healthy
+func (m *Monitor) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Alerts returns the conditions that are currently active
+func (m *Monitor) Alerts() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alerts := make([]Event, 0, len(m.active))
+	for _, event := range m.active {
+		alerts = append(alerts, event)
+	}
+	slices.SortFunc(alerts, func(a, b Event) int { return a.Time.Compare(b.Time) })
+	return alerts
+}
+
+// Status returns the latest state of every node, in network order
+func (m *Monitor) Status() []NodeStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := make([]NodeStatus, 0, len(m.status))
+	for _, nodeCfg := range m.networkCfg.Nodes {
+		if s, ok := m.status[nodeCfg.GetID()]; ok {
+			status = append(status, *s)
+		}
+	}
+	return status
+}
+
+// Stop stops polling and waits for the current poll to finish
+func (m *Monitor) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.done)
+	defer close(m.events)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.poll()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll queries every node in parallel and updates the alerts
+func (m *Monitor) poll() {
+	nodes := m.networkCfg.Nodes
+	for _, nodeCfg := range nodes {
+		if _, ok := m.clients[nodeCfg.GetID()]; !ok {
+			m.clients[nodeCfg.GetID()] = thorclient.New(nodeCfg.GetHTTPAddr())
+		}
+	}
+
+	expectedPeers, err := m.networkCfg.ExpectedPeers()
+	if err != nil {
+		slog.Warn("monitor unable to resolve expected peers", "error", err)
+	}
+
+	statuses := make([]*NodeStatus, len(nodes))
+	timestamps := make([]uint64, len(nodes))
+	var wg sync.WaitGroup
+	for i, nodeCfg := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], timestamps[i] = m.query(nodeCfg.GetID())
+		}()
+	}
+	wg.Wait()
+	fetched := m.fetchDivergence(statuses)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+
+	for i, s := range statuses {
+		s.ExpectedPeers = len(expectedPeers[s.ID])
+		if s.Reachable {
+			s.BlockInterval = m.cadence(s.ID, s.Best, timestamps[i])
+		}
+		m.status[s.ID] = s
+	}
+
+	// The chain stalls when no node extends the best block
+	var best uint32
+	for _, s := range statuses {
+		best = max(best, s.Best)
+	}
+	if best > m.best {
+		m.best, m.progress = best, now
+	}
+	if stalled := now.Sub(m.progress); stalled > m.cfg.StallAfter {
+		m.raise(KindStall, "", m.best, fmt.Sprintf("no new block for %s, best block is %d", stalled.Round(time.Millisecond), m.best))
+	} else {
+		m.resolve(KindStall, "", m.best)
+	}
+
+	checkPeers := !m.networkCfg.IsPublicNetwork() && len(nodes) > 1
+	for _, s := range statuses {
+		if !s.Reachable {
+			m.raise(KindUnreachable, s.ID, 0, fmt.Sprintf("node %s is unreachable", s.ID))
+			continue
+		}
+		m.resolve(KindUnreachable, s.ID, s.Best)
+
+		if m.best > s.Best+m.cfg.MaxLag {
+			m.raise(KindLagging, s.ID, s.Best, fmt.Sprintf("node %s is at block %d, %d blocks behind the best block %d", s.ID, s.Best, m.best-s.Best, m.best))
+		} else {
+			m.resolve(KindLagging, s.ID, s.Best)
+		}
+
+		if checkPeers && s.PeerCount < s.ExpectedPeers {
+			m.raise(KindPeersDropped, s.ID, s.Best, fmt.Sprintf("node %s has %d peers, expected %d", s.ID, s.PeerCount, s.ExpectedPeers))
+		} else {
+			m.resolve(KindPeersDropped, s.ID, s.Best)
+		}
+	}
+
+	m.checkDivergence(statuses, fetched)
+}
+
+// query fetches the state of a node, and the timestamp of its best block
+func (m *Monitor) query(id string) (*NodeStatus, uint64) {
+	client := m.clients[id]
+	status := &NodeStatus{ID: id}
+
+	best, err := client.Block("best")
+	if err != nil || best == nil {
+		return status, 0
+	}
+	status.Reachable = true
+	status.Best = best.Number
+	metrics.SetBlockHeight(id, best.Number)
+
+	if finalized, err := client.Block("finalized"); err == nil && finalized != nil {
+		status.Finalized = finalized.Number
+	}
+	if peers, err := client.Peers(); err == nil {
+		status.PeerCount = len(peers)
+	}
+	return status, best.Timestamp
+}
+
+// cadence returns the average block interval of a node since it was first seen, the caller must hold the lock
+func (m *Monitor) cadence(id string, number uint32, timestamp uint64) time.Duration {
+	first, ok := m.first[id]
+	if !ok || number < first.number {
+		m.first[id] = baseline{number: number, timestamp: timestamp}
+		return 0
+	}
+	if number == first.number || timestamp < first.timestamp {
+		return 0
+	}
+	return time.Duration(timestamp-first.timestamp) * time.Second / time.Duration(number-first.number)
+}
+
+// groupBlocks holds the block the reachable nodes of a partition group have at the same height
+type groupBlocks struct {
+	height uint32
+	nodes  []string // nodes that returned their block, in status order
+	blocks []string
+}
+
+// fetchDivergence fetches the block each node holds Confirmations below the lowest best block of its
+// partition group. It queries the nodes, so it runs without the lock.
+func (m *Monitor) fetchDivergence(statuses []*NodeStatus) []groupBlocks {
+	groups := make(map[int][]*NodeStatus)
+	for _, s := range statuses {
+		if s.Reachable {
+			group := m.group(s.ID)
+			groups[group] = append(groups[group], s)
+		}
+	}
+
+	var fetched []groupBlocks
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		lowest := group[0].Best
+		for _, s := range group {
+			lowest = min(lowest, s.Best)
+		}
+		if lowest < m.cfg.Confirmations {
+			continue
+		}
+
+		blocks := groupBlocks{height: lowest - m.cfg.Confirmations}
+		for _, s := range group {
+			blk, err := m.clients[s.ID].Block(fmt.Sprintf("%d", blocks.height))
+			if err != nil || blk == nil {
+				continue
+			}
+			blocks.nodes = append(blocks.nodes, s.ID)
+			blocks.blocks = append(blocks.blocks, blk.ID.String())
+		}
+		fetched = append(fetched, blocks)
+	}
+	return fetched
+}
+
+// checkDivergence raises a divergence for nodes whose block differs from the first node of their group,
+// the caller must hold the lock
+func (m *Monitor) checkDivergence(statuses []*NodeStatus, fetched []groupBlocks) {
+	diverged := make(map[string]bool)
+	for _, group := range fetched {
+		for i := 1; i < len(group.nodes); i++ {
+			if group.blocks[i] != group.blocks[0] {
+				diverged[group.nodes[i]] = true
+				m.raise(KindDivergence, group.nodes[i], group.height, fmt.Sprintf(
+					"node %s has block %s at height %d, node %s has %s",
+					group.nodes[i], group.blocks[i], group.height, group.nodes[0], group.blocks[0],
+				))
+			}
+		}
+	}
+
+	for _, s := range statuses {
+		if s.Reachable && !diverged[s.ID] {
+			m.resolve(KindDivergence, s.ID, s.Best)
+		}
+	}
+}
+
+// group returns the index of the partition group of a node, 0 when the network is not partitioned
+func (m *Monitor) group(id string) int {
+	for i, group := range m.networkCfg.Partition() {
+		if slices.Contains(group, id) {
+			return i
+		}
+	}
+	return 0
+}
+
+// raise reports a condition unless it is already active, the caller must hold the lock
+func (m *Monitor) raise(kind Kind, node string, block uint32, message string) {
+	key := alertKey{kind: kind, node: node}
+	if _, ok := m.active[key]; ok {
+		return
+	}
+
+	event := Event{Time: time.Now(), Kind: kind, Node: node, Block: block, Message: message}
+	m.active[key] = event
+	if m.err == nil {
+		m.err = event
+	}
+	slog.Warn("network alert", "kind", kind, "node", node, "block", block, "message", message)
+	m.send(event)
+}
+
+// resolve reports the end of an active condition, the caller must hold the lock
+func (m *Monitor) resolve(kind Kind, node string, block uint32) {
+	key := alertKey{kind: kind, node: node}
+	alert, ok := m.active[key]
+	if !ok {
+		return
+	}
+	delete(m.active, key)
+
+	event := Event{Time: time.Now(), Kind: kind, Node: node, Block: block, Message: alert.Message, Resolved: true}
+	slog.Info("network alert resolved", "kind", kind, "node", node, "block", block)
+	m.send(event)
+}
+
+func (m *Monitor) send(event Event) {
+	select {
+	case m.events <- event:
+	default:
+		slog.Warn("monitor event dropped, the events channel is full", "kind", event.Kind, "node", event.Node)
+	}
+}
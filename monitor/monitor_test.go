@@ -0,0 +1,126 @@
+package monitor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/internal/environments/fake"
+	"github.com/vechain/networkhub/internal/environments/fake/faketest"
+	"github.com/vechain/networkhub/monitor"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/preset"
+)
+
+// startFakeNetwork starts the three node preset on fake nodes
+func startFakeNetwork(t *testing.T) (*network.Network, map[string]*fake.Node) {
+	networkCfg := preset.LocalThreeNodesNetwork()
+	_, nodes := faketest.StartClient(t, networkCfg)
+	return networkCfg, nodes
+}
+
+// nextEvent waits for the next monitor event
+func nextEvent(t *testing.T, m *monitor.Monitor) monitor.Event {
+	select {
+	case event := <-m.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no monitor event")
+		return monitor.Event{}
+	}
+}
+
+func TestMonitorAlerts(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+	produce := func(count int) {
+		for _, n := range nodes {
+			n.ProduceBlocks(count)
+		}
+	}
+	produce(2)
+
+	m, err := monitor.Start(context.Background(), networkCfg, monitor.Config{
+		Interval:   10 * time.Millisecond,
+		StallAfter: time.Hour,
+	})
+	require.NoError(t, err)
+	defer m.Stop()
+
+	require.Eventually(t, func() bool { return len(m.Status()) == 3 }, time.Second, 10*time.Millisecond)
+	status := m.Status()[0]
+	assert.Equal(t, "node1", status.ID)
+	assert.Equal(t, uint32(2), status.Best)
+	assert.Equal(t, 2, status.PeerCount)
+	assert.Equal(t, 2, status.ExpectedPeers)
+
+	// The network moves on without node3
+	nodes["node1"].ProduceBlocks(3)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	event := nextEvent(t, m)
+	assert.Equal(t, monitor.KindLagging, event.Kind)
+	assert.Equal(t, "node3", event.Node)
+	assert.Equal(t, "node node3 is at block 2, 3 blocks behind the best block 5", event.Message)
+
+	nodes["node3"].SyncFrom(nodes["node1"])
+	event = nextEvent(t, m)
+	assert.Equal(t, monitor.KindLagging, event.Kind)
+	assert.True(t, event.Resolved)
+
+	// Each block interval is 10s in the preset genesis
+	require.Eventually(t, func() bool { return m.Status()[0].BlockInterval == 10*time.Second }, time.Second, 10*time.Millisecond)
+
+	nodes["node2"].SetPeers("node1")
+	event = nextEvent(t, m)
+	assert.Equal(t, monitor.KindPeersDropped, event.Kind)
+	assert.Equal(t, "node node2 has 1 peers, expected 2", event.Message)
+	nodes["node2"].SetPeers("node1", "node3")
+	assert.True(t, nextEvent(t, m).Resolved)
+
+	require.NoError(t, nodes["node3"].Fork(3, "minority", 2))
+	nodes["node1"].ProduceBlocks(1)
+	nodes["node2"].SyncFrom(nodes["node1"])
+	event = nextEvent(t, m)
+	assert.Equal(t, monitor.KindDivergence, event.Kind)
+	assert.Equal(t, "node3", event.Node)
+	assert.Equal(t, uint32(4), event.Block)
+
+	// The first alert fails the test with its reason
+	var first monitor.Event
+	require.True(t, errors.As(m.Err(), &first))
+	assert.Equal(t, monitor.KindLagging, first.Kind)
+	require.Len(t, m.Alerts(), 1)
+}
+
+func TestMonitorStall(t *testing.T) {
+	networkCfg, nodes := startFakeNetwork(t)
+
+	m, err := monitor.Start(context.Background(), networkCfg, monitor.Config{
+		Interval:   10 * time.Millisecond,
+		StallAfter: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	event := nextEvent(t, m)
+	assert.Equal(t, monitor.KindStall, event.Kind)
+	assert.Empty(t, event.Node)
+
+	for _, n := range nodes {
+		n.ProduceBlocks(1)
+	}
+	event = nextEvent(t, m)
+	assert.Equal(t, monitor.KindStall, event.Kind)
+	assert.True(t, event.Resolved)
+
+	nodes["node1"].SetOffline(true)
+	event = nextEvent(t, m)
+	assert.Equal(t, monitor.KindUnreachable, event.Kind)
+	assert.Equal(t, "node1", event.Node)
+
+	// Stopping closes the events channel
+	m.Stop()
+	for range m.Events() {
+	}
+}
@@ -0,0 +1,270 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thorclient"
+)
+
+// maxBranchBlocks caps the blocks listed per branch of a fork report
+const maxBranchBlocks = 64
+
+// ForkBlock is a block of a competing branch
+type ForkBlock struct {
+	Number     uint32 `json:"number"`
+	ID         string `json:"id"`
+	ParentID   string `json:"parentID"`
+	Signer     string `json:"signer"`
+	Timestamp  uint64 `json:"timestamp"`
+	TotalScore uint64 `json:"totalScore"`
+}
+
+// Branch is a chain the nodes of Nodes follow from the fork point
+type Branch struct {
+	Nodes  []string    `json:"nodes"`
+	Length uint32      `json:"length"` // blocks after the fork point up to the best block of the branch
+	Blocks []ForkBlock `json:"blocks"` // the first blocks of the branch, in order, at most 64
+}
+
+// ForkReport describes where the chains of the nodes diverge
+type ForkReport struct {
+	Forked bool `json:"forked"`
+	// Ancestor is the last block shared by the chains of the nodes, leaving out nodes that are merely behind
+	// another one: the fork point when Forked, the best block of the most advanced node otherwise
+	Ancestor ForkBlock `json:"ancestor"`
+	// Branches are the competing chains after the ancestor. Nodes whose best block is at or below the ancestor
+	// are behind rather than forked, they are in no branch.
+	Branches []Branch `json:"branches,omitempty"`
+}
+
+// Branch returns the branch the node follows, nil if it is on none
+func (r *ForkReport) Branch(nodeID string) *Branch {
+	for i := range r.Branches {
+		if slices.Contains(r.Branches[i].Nodes, nodeID) {
+			return &r.Branches[i]
+		}
+	}
+	return nil
+}
+
+// DetectForks finds the last common ancestor of the nodes' chains and the competing branches built on it.
+// The network is forked when the nodes follow more than one branch, including across partition groups.
+func (n *Network) DetectForks() (*ForkReport, error) {
+	if len(n.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes defined in the network")
+	}
+
+	clients := make([]*thorclient.Client, len(n.Nodes))
+	bests := make([]*api.JSONCollapsedBlock, len(n.Nodes))
+	for i, node := range n.Nodes {
+		clients[i] = thorclient.New(node.GetHTTPAddr())
+		best, err := clients[i].Block("best")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get best block from node %s: %w", node.GetID(), err)
+		}
+		if best == nil {
+			return nil, fmt.Errorf("node %s has no best block", node.GetID())
+		}
+		bests[i] = best
+	}
+
+	// A node whose best block is on the chain of a more advanced node is merely behind it. Only the tips,
+	// chains no other node extends, take part in the search, a lagging node would hide forks above its best block.
+	order := make([]int, len(n.Nodes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return int(bests[b].Number) - int(bests[a].Number) })
+
+	var tips []int
+	for _, i := range order {
+		behind := false
+		for _, tip := range tips {
+			blk, err := blockAt(clients[tip], n.Nodes[tip].GetID(), bests[i].Number)
+			if err != nil {
+				return nil, err
+			}
+			if blk.ID == bests[i].ID {
+				behind = true
+				break
+			}
+		}
+		if !behind {
+			tips = append(tips, i)
+		}
+	}
+	if len(tips) == 1 {
+		return &ForkReport{Ancestor: forkBlock(bests[tips[0]])}, nil
+	}
+
+	lowest := bests[tips[0]].Number
+	for _, tip := range tips {
+		lowest = min(lowest, bests[tip].Number)
+	}
+
+	// Chains agree up to the fork point and differ from there on, so the ancestor is found by bisection
+	var searchErr error
+	diverged := sort.Search(int(lowest)+1, func(height int) bool {
+		if searchErr != nil {
+			return true
+		}
+		blocks, err := n.blocksAt(clients, tips, uint32(height))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return slices.ContainsFunc(blocks, func(blk *api.JSONCollapsedBlock) bool { return blk.ID != blocks[0].ID })
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	if diverged == 0 {
+		return nil, fmt.Errorf("nodes do not share a genesis block")
+	}
+
+	ancestorHeight := uint32(diverged - 1)
+	ancestor, err := blockAt(clients[tips[0]], n.Nodes[tips[0]].GetID(), ancestorHeight)
+	if err != nil {
+		return nil, err
+	}
+	report := &ForkReport{Ancestor: forkBlock(ancestor)}
+
+	// Nodes are on the same branch when they hold the same block right after the ancestor
+	branches := make(map[string]int)
+	for i, node := range n.Nodes {
+		if bests[i].Number <= ancestorHeight {
+			continue
+		}
+		first, err := blockAt(clients[i], node.GetID(), ancestorHeight+1)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := branches[first.ID.String()]
+		if !ok {
+			index = len(report.Branches)
+			branches[first.ID.String()] = index
+			report.Branches = append(report.Branches, Branch{})
+		}
+		branch := &report.Branches[index]
+		branch.Nodes = append(branch.Nodes, node.GetID())
+
+		// The branch is listed from its most advanced node
+		if length := bests[i].Number - ancestorHeight; length > branch.Length {
+			blocks, err := branchBlocks(clients[i], ancestorHeight+1, min(length, maxBranchBlocks))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get branch of node %s: %w", node.GetID(), err)
+			}
+			branch.Length, branch.Blocks = length, blocks
+		}
+	}
+
+	report.Forked = len(report.Branches) > 1
+	return report, nil
+}
+
+// ForkResolution tells whether an earlier fork has resolved: every node now follows one of its branches.
+// It returns the winning branch, nil while the nodes still disagree.
+func (n *Network) ForkResolution(fork *ForkReport) (*Branch, error) {
+	if !fork.Forked {
+		return nil, fmt.Errorf("the report has no fork")
+	}
+
+	current, err := n.DetectForks()
+	if err != nil {
+		return nil, err
+	}
+	if current.Forked || current.Ancestor.Number <= fork.Ancestor.Number {
+		return nil, nil
+	}
+
+	// Every node past the old fork point is on one chain, the winner is the branch holding its block there
+	var first *api.JSONCollapsedBlock
+	for _, node := range n.Nodes {
+		client := thorclient.New(node.GetHTTPAddr())
+		best, err := client.Block("best")
+		if err != nil || best == nil || best.Number <= fork.Ancestor.Number {
+			continue
+		}
+		if first, err = blockAt(client, node.GetID(), fork.Ancestor.Number+1); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if first == nil {
+		return nil, nil
+	}
+	for i := range fork.Branches {
+		if len(fork.Branches[i].Blocks) > 0 && fork.Branches[i].Blocks[0].ID == first.ID.String() {
+			return &fork.Branches[i], nil
+		}
+	}
+	return nil, fmt.Errorf("nodes converged on block %s which is on none of the fork's branches", first.ID)
+}
+
+// blocksAt fetches the block each of the given nodes holds at the given height, in parallel
+func (n *Network) blocksAt(clients []*thorclient.Client, nodes []int, height uint32) ([]*api.JSONCollapsedBlock, error) {
+	blocks := make([]*api.JSONCollapsedBlock, len(nodes))
+	errs := make([]error, len(nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blocks[i], errs[i] = blockAt(clients[node], n.Nodes[node].GetID(), height)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// blockAt fetches the block a node holds at the given height on its chain
+func blockAt(client *thorclient.Client, nodeID string, height uint32) (*api.JSONCollapsedBlock, error) {
+	blk, err := client.Block(strconv.FormatUint(uint64(height), 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d from node %s: %w", height, nodeID, err)
+	}
+	if blk == nil {
+		return nil, fmt.Errorf("node %s has no block %d", nodeID, height)
+	}
+	return blk, nil
+}
+
+// branchBlocks fetches count blocks of the node's chain starting at the given height
+func branchBlocks(client *thorclient.Client, from, count uint32) ([]ForkBlock, error) {
+	blocks := make([]ForkBlock, 0, count)
+	for height := from; height < from+count; height++ {
+		blk, err := client.Block(strconv.FormatUint(uint64(height), 10))
+		if err != nil {
+			return nil, err
+		}
+		if blk == nil {
+			break // the node reorganised while it was being read
+		}
+		blocks = append(blocks, forkBlock(blk))
+	}
+	return blocks, nil
+}
+
+func forkBlock(blk *api.JSONCollapsedBlock) ForkBlock {
+	return ForkBlock{
+		Number:     blk.Number,
+		ID:         blk.ID.String(),
+		ParentID:   blk.ParentID.String(),
+		Signer:     blk.Signer.String(),
+		Timestamp:  blk.Timestamp,
+		TotalScore: blk.TotalScore,
+	}
+}
@@ -0,0 +1,196 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	nodegenesis "github.com/vechain/networkhub/network/node/genesis"
+	"github.com/vechain/thor/v2/api"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/httpclient"
+)
+
+// CheckGenesis builds the genesis of the nodes offline and checks every keyed node is a validator of it,
+// see genesis.Check, and that the thor release of each node accepts it. It returns the genesis ID, which all
+// nodes must share, or zero when thor could not build it. Public networks have no genesis to check.
+func (n *Network) CheckGenesis() (thor.Bytes32, error) {
+	if n.IsPublicNetwork() {
+		return thor.Bytes32{}, nil
+	}
+
+	// Nodes usually share a genesis, check each one once with the keys of its nodes
+	var (
+		genesisOrder []*nodegenesis.CustomGenesis
+		keys         = make(map[*nodegenesis.CustomGenesis]map[string]string)
+	)
+	for _, node := range n.Nodes {
+		gen := node.GetGenesis()
+		if gen == nil {
+			return thor.Bytes32{}, fmt.Errorf("node %s has no genesis", node.GetID())
+		}
+		// A node running another thor release must be able to read its genesis file
+		if _, err := nodegenesis.MarshalFor(gen, node.GetThorVersion()); err != nil {
+			return thor.Bytes32{}, fmt.Errorf("invalid genesis for node %s: %w", node.GetID(), err)
+		}
+		if keys[gen] == nil {
+			keys[gen] = make(map[string]string)
+			genesisOrder = append(genesisOrder, gen)
+		}
+		keys[gen][node.GetID()] = node.GetKey()
+	}
+
+	var (
+		genesisID thor.Bytes32
+		groups    []string
+		mismatch  bool
+	)
+	for _, gen := range genesisOrder {
+		nodeIDs := strings.Join(slices.Sorted(maps.Keys(keys[gen])), ", ")
+		id, err := nodegenesis.Check(gen, keys[gen])
+		if err != nil {
+			return thor.Bytes32{}, fmt.Errorf("invalid genesis for nodes %s: %w", nodeIDs, err)
+		}
+		groups = append(groups, fmt.Sprintf("%s for nodes %s", id, nodeIDs))
+		// Only compare the IDs thor could build
+		if id.IsZero() {
+			continue
+		}
+		if genesisID.IsZero() {
+			genesisID = id
+		}
+		mismatch = mismatch || id != genesisID
+	}
+	if mismatch {
+		return thor.Bytes32{}, fmt.Errorf("nodes run different genesis blocks: %s", strings.Join(groups, "; "))
+	}
+	return genesisID, nil
+}
+
+// ExportAccounts reads the accounts at the block revision (a number, ID or "best") from the first node, as
+// genesis accounts. Addresses default to the accounts of the first node's genesis. Contract storage is listed
+// with thor's /debug/storage-range, which reads the state before a transaction: the storage after the block is
+// read at the first later block carrying a transaction, and exporting contracts fails until there is one.
+func (n *Network) ExportAccounts(revision string, addresses ...thor.Address) ([]thorgenesis.Account, error) {
+	if len(n.Nodes) == 0 {
+		return nil, fmt.Errorf("network has no nodes")
+	}
+	first := n.Nodes[0]
+
+	if gen := first.GetGenesis(); gen != nil && len(addresses) == 0 {
+		seen := make(map[thor.Address]bool)
+		for _, account := range gen.Accounts {
+			if !seen[account.Address] {
+				seen[account.Address] = true
+				addresses = append(addresses, account.Address)
+			}
+		}
+	}
+
+	// Pin the block, so every account is read at the same state even if revision is "best"
+	client := thorclient.New(first.GetHTTPAddr())
+	blk, err := client.Block(revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s from node %s: %w", revision, first.GetID(), err)
+	}
+	if blk == nil {
+		return nil, fmt.Errorf("node %s has no block %s", first.GetID(), revision)
+	}
+	at := thorclient.Revision(blk.ID.String())
+
+	var storageTarget string
+	accounts := make([]thorgenesis.Account, 0, len(addresses))
+	for _, addr := range addresses {
+		account, err := client.Account(&addr, at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account %s: %w", addr, err)
+		}
+		exported := thorgenesis.Account{
+			Address: addr,
+			Balance: (*thorgenesis.HexOrDecimal256)((*big.Int)(account.Balance)),
+			Energy:  (*thorgenesis.HexOrDecimal256)((*big.Int)(account.Energy)),
+		}
+
+		if account.HasCode {
+			code, err := client.AccountCode(&addr, at)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get code of %s: %w", addr, err)
+			}
+			exported.Code = code.Code
+
+			if storageTarget == "" {
+				if storageTarget, err = stateAfter(client, blk); err != nil {
+					return nil, fmt.Errorf("cannot list the storage of contract %s: %w", addr, err)
+				}
+			}
+			if exported.Storage, err = storageRange(client, addr, storageTarget); err != nil {
+				return nil, fmt.Errorf("failed to list the storage of %s: %w", addr, err)
+			}
+		}
+		accounts = append(accounts, exported)
+	}
+	return accounts, nil
+}
+
+// stateAfter returns the /debug/storage-range target of the state after the block: the first transaction of
+// the next block carrying one, as blocks without transactions leave contract storage unchanged
+func stateAfter(client *thorclient.Client, blk *api.JSONCollapsedBlock) (string, error) {
+	parent := blk.ID
+	for number := blk.Number + 1; ; number++ {
+		next, err := client.Block(strconv.FormatUint(uint64(number), 10))
+		if errors.Is(err, httpclient.ErrNotFound) {
+			return "", fmt.Errorf("thor's /debug/storage-range reads storage before a transaction, and no block after %d carries one yet", blk.Number)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get block %d: %w", number, err)
+		}
+		if next.ParentID != parent {
+			return "", fmt.Errorf("block %d is no longer on the chain of block %s", number, blk.ID)
+		}
+		if len(next.Transactions) > 0 {
+			return next.ID.String() + "/0/0", nil
+		}
+		parent = next.ID
+	}
+}
+
+// storageRange lists the storage of the account at the target, one page of thor's /debug/storage-range at a time
+func storageRange(client *thorclient.Client, addr thor.Address, target string) (map[string]thor.Bytes32, error) {
+	storage := make(map[string]thor.Bytes32)
+	opt := api.StorageRangeOption{Address: addr, Target: target}
+	for {
+		body, status, err := client.RawHTTPClient().RawHTTPPost("/debug/storage-range", &opt)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("/debug/storage-range returned %d: %s", status, strings.TrimSpace(string(body)))
+		}
+		var result api.StorageRangeResult
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("invalid /debug/storage-range response: %w", err)
+		}
+		for _, entry := range result.Storage {
+			if entry.Key == nil || entry.Value == nil {
+				return nil, fmt.Errorf("/debug/storage-range returned an entry without its key")
+			}
+			storage[entry.Key.String()] = *entry.Value
+		}
+		if result.NextKey == nil {
+			break
+		}
+		opt.KeyStart = result.NextKey.String()
+	}
+	if len(storage) == 0 {
+		return nil, nil
+	}
+	return storage, nil
+}
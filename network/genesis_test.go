@@ -0,0 +1,186 @@
+package network
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/genesisbuilder"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/network/node/genesis"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// validatorsNetwork returns a network of n validators sharing the genesis
+func validatorsNetwork(validators []genesisbuilder.ValidatorKeys, gen *genesis.CustomGenesis) *Network {
+	net := &Network{BaseID: "check"}
+	for i, validator := range validators {
+		net.Nodes = append(net.Nodes, validator.Node(string(rune('a'+i)), gen))
+	}
+	return net
+}
+
+func TestCheckGenesis(t *testing.T) {
+	validators := genesisbuilder.DeriveValidators("check", 3)
+	hayabusaTP := uint32(0)
+	posConfig := &genesis.Config{LowStakingPeriod: 10, MediumStakingPeriod: 20, HighStakingPeriod: 40, HayabusaTP: &hayabusaTP}
+	authority := thorgenesis.Authority{
+		MasterAddress:   *validators[0].Master.Address,
+		EndorsorAddress: *validators[0].Endorser.Address,
+		Identity:        thor.BytesToBytes32([]byte("a")),
+	}
+
+	gen := genesisbuilder.New(3).Validators(validators).Config(posConfig).Build()
+	net := validatorsNetwork(validators, gen)
+	id, err := net.CheckGenesis()
+	require.NoError(t, err)
+	assert.NotEqual(t, thor.Bytes32{}, id)
+
+	// The check does not leak the genesis config into the process
+	assert.NotEqual(t, uint32(40), thor.HighStakingPeriod())
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		net := validatorsNetwork(validators, gen)
+		net.Nodes = append(net.Nodes, genesisbuilder.DeriveValidators("other", 1)[0].Node("observer", gen))
+		_, err := net.CheckGenesis()
+		assert.ErrorContains(t, err, "node observer key maps to")
+
+		// Nodes without a key don't produce blocks and are fine
+		net.Nodes[3].(*node.BaseNode).Key = ""
+		_, err = net.CheckGenesis()
+		assert.NoError(t, err)
+	})
+
+	t.Run("MaxBlockProposers", func(t *testing.T) {
+		gen := genesisbuilder.New(2).Validators(validators).Config(posConfig).Build()
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		assert.ErrorContains(t, err, "maxBlockProposers 2 is below the 3 validator nodes")
+	})
+
+	t.Run("StakingPeriods", func(t *testing.T) {
+		config := *posConfig
+		config.MediumStakingPeriod = 40
+		gen := genesisbuilder.New(3).Validators(validators).Config(&config).Build()
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		assert.ErrorContains(t, err, "staking periods must increase from low to high, got low 10, medium 40 and high 40")
+	})
+
+	t.Run("StakersBeforeHayabusa", func(t *testing.T) {
+		forkConfig := thor.SoloFork
+		forkConfig.HAYABUSA = 100
+		gen := genesisbuilder.New(3).Validators(validators).Config(posConfig).
+			ForkConfig(&genesis.CustomGenesisForkConfig{ForkConfig: forkConfig}).Build()
+		gen.Authority = []thorgenesis.Authority{authority}
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		assert.ErrorContains(t, err, "stakers are only registered when the HAYABUSA fork is at block 0, it is at 100")
+	})
+
+	t.Run("AuthorityEndorsement", func(t *testing.T) {
+		forkConfig := thor.SoloFork
+		forkConfig.HAYABUSA = 100
+		endorsement := big.NewInt(1e18)
+		gen := genesisbuilder.New(1).
+			Accounts([]thorgenesis.Account{{Address: *validators[0].Endorser.Address, Balance: (*thorgenesis.HexOrDecimal256)(big.NewInt(1))}}).
+			Params(thorgenesis.Params{ProposerEndorsement: (*thorgenesis.HexOrDecimal256)(endorsement)}).
+			ForkConfig(&genesis.CustomGenesisForkConfig{ForkConfig: forkConfig}).
+			Build()
+		gen.Stakers = nil
+		gen.Authority = []thorgenesis.Authority{authority}
+		_, err := validatorsNetwork(validators[:1], gen).CheckGenesis()
+		assert.ErrorContains(t, err, "holds 1, below the proposer endorsement 1000000000000000000")
+	})
+
+	t.Run("Authority", func(t *testing.T) {
+		forkConfig := thor.SoloFork
+		forkConfig.HAYABUSA = 100
+		gen := genesisbuilder.New(3).AuthorityValidators(validators).
+			ForkConfig(&genesis.CustomGenesisForkConfig{ForkConfig: forkConfig}).
+			Approvers([]thorgenesis.Approver{{Address: *validators[0].Endorser.Address, Identity: thor.BytesToBytes32([]byte("approver"))}}).
+			Build()
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		require.NoError(t, err)
+
+		_, err = validatorsNetwork(validators[:2], gen).CheckGenesis()
+		assert.ErrorContains(t, err, "authority "+validators[2].Master.Address.String()+" has no node in the network")
+	})
+
+	t.Run("DifferentGenesis", func(t *testing.T) {
+		other := genesisbuilder.New(3).Validators(validators).Config(posConfig).GasLimit(1_000_000).Build()
+		net := validatorsNetwork(validators, gen)
+		net.Nodes[2].SetGenesis(other)
+		_, err := net.CheckGenesis()
+		assert.ErrorContains(t, err, "nodes run different genesis blocks")
+	})
+
+	t.Run("ThorVersion", func(t *testing.T) {
+		net := validatorsNetwork(validators, gen)
+		net.Nodes[1].SetThorVersion("v2.3.0")
+		_, err := net.CheckGenesis()
+		assert.ErrorContains(t, err, "invalid genesis for node b: thor v2.3.0 does not support the genesis fields")
+	})
+
+	t.Run("LeftoverConfig", func(t *testing.T) {
+		// A configuration left in the process by someone else changes neither the ID nor the checks
+		previous := thor.MediumStakingPeriod()
+		thor.SetConfig(thor.Config{MediumStakingPeriod: 1})
+		t.Cleanup(func() { thor.SetConfig(thor.Config{MediumStakingPeriod: previous}) })
+
+		config := *posConfig
+		config.MediumStakingPeriod = 0
+		gen := genesisbuilder.New(3).Validators(validators).Config(&config).Build()
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		assert.ErrorContains(t, err, "got low 10, medium 129600 and high 40")
+
+		leftoverID, err := net.CheckGenesis()
+		require.NoError(t, err)
+		assert.Equal(t, id, leftoverID)
+		assert.Equal(t, uint32(1), thor.MediumStakingPeriod())
+	})
+
+	t.Run("InProcessNodes", func(t *testing.T) {
+		held, _, err := genesis.ToThor(gen)
+		require.NoError(t, err)
+		release, err := genesis.HoldConfig(held.Config)
+		require.NoError(t, err)
+		defer release()
+
+		// The held config is the genesis one, the check runs as usual
+		heldID, err := net.CheckGenesis()
+		require.NoError(t, err)
+		assert.Equal(t, id, heldID)
+
+		// Thor cannot build a genesis on another config, only its ID is left unchecked
+		config := *posConfig
+		config.CooldownPeriod = 5
+		other := validatorsNetwork(validators, genesisbuilder.New(3).Validators(validators).Config(&config).Build())
+		otherID, err := other.CheckGenesis()
+		require.NoError(t, err)
+		assert.Equal(t, thor.Bytes32{}, otherID)
+
+		_, err = genesis.HoldConfig(&thor.Config{CooldownPeriod: 5})
+		assert.ErrorContains(t, err, "in-process nodes are running on another thor configuration")
+
+		broken := config
+		broken.MediumStakingPeriod = 40
+		_, err = validatorsNetwork(validators, genesisbuilder.New(3).Validators(validators).Config(&broken).Build()).CheckGenesis()
+		assert.ErrorContains(t, err, "staking periods must increase from low to high")
+
+		_, err = genesis.HoldConfig(nil)
+		assert.ErrorContains(t, err, "in-process nodes are running on another thor configuration")
+
+		// The last release restores the previous config
+		release()
+		assert.NotEqual(t, uint32(40), thor.HighStakingPeriod())
+		otherID, err = other.CheckGenesis()
+		require.NoError(t, err)
+		assert.NotEqual(t, thor.Bytes32{}, otherID)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		gen := genesisbuilder.New(3).Validators(validators).Config(&genesis.Config{EpochLength: 1}).Build()
+		_, err := validatorsNetwork(validators, gen).CheckGenesis()
+		assert.ErrorContains(t, err, "thor rejects the genesis: EpochLength can not be zero or one")
+	})
+}
@@ -0,0 +1,234 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vechain/networkhub/metrics"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thorclient"
+)
+
+// BlockRef identifies a block seen by a node
+type BlockRef struct {
+	Number    uint32 `json:"number"`
+	ID        string `json:"id"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// NodeHealth is the state of a node at the time of a health report
+type NodeHealth struct {
+	ID            string    `json:"id"`
+	Reachable     bool      `json:"reachable"`
+	Error         string    `json:"error,omitempty"` // why the node could not be queried
+	Best          *BlockRef `json:"best,omitempty"`
+	Finalized     *BlockRef `json:"finalized,omitempty"`
+	Block         *BlockRef `json:"block,omitempty"` // block at the requested height, nil if not reached yet
+	PeerCount     int       `json:"peerCount"`
+	Peers         []string  `json:"peers"` // node IDs of the peers, p2p IDs for peers outside the network
+	ExpectedPeers []string  `json:"expectedPeers,omitempty"`
+}
+
+// HealthReport is the result of checking every node of the network at a block height
+type HealthReport struct {
+	Block    uint32       `json:"block"`
+	Time     time.Time    `json:"time"`
+	Healthy  bool         `json:"healthy"`
+	Nodes    []NodeHealth `json:"nodes"`
+	Problems []string     `json:"problems,omitempty"`
+}
+
+// Err summarizes the problems of the report as an error, nil when the network is healthy
+func (r *HealthReport) Err() error {
+	if r.Healthy {
+		return nil
+	}
+	return fmt.Errorf("network unhealthy at block %d: %s", r.Block, strings.Join(r.Problems, "; "))
+}
+
+// Node returns the health of the node with the given ID, nil if the report has no such node
+func (r *HealthReport) Node(id string) *NodeHealth {
+	for i := range r.Nodes {
+		if r.Nodes[i].ID == id {
+			return &r.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// HealthCheck waits until every node has reached the block, peers as its topology expects and
+// agrees on the block with the nodes it can reach. It returns the problems of the last report on timeout.
+func (n *Network) HealthCheck(block uint32, timeout time.Duration) error {
+	report, err := n.HealthReport(block, timeout)
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
+
+// HealthReport polls the nodes until the network is healthy at the given block or the timeout expires,
+// and returns the last report either way
+func (n *Network) HealthReport(block uint32, timeout time.Duration) (*HealthReport, error) {
+	if len(n.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes defined in the network")
+	}
+
+	expectedPeers, err := n.ExpectedPeers()
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	clients := make([]*thorclient.Client, len(n.Nodes))
+	for i, node := range n.Nodes {
+		clients[i] = thorclient.New(node.GetHTTPAddr())
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	// Poll right away so healthy networks don't wait for the first tick
+	for {
+		report := n.report(clients, block, expectedPeers)
+		if report.Healthy || time.Now().After(deadline) {
+			metrics.ObserveHealthCheck(time.Since(start), report.Healthy)
+			n.mu.Lock()
+			n.lastHealth = report
+			n.mu.Unlock()
+			return report, nil
+		}
+
+		select {
+		case <-time.After(time.Until(deadline)):
+		case <-ticker.C:
+		}
+	}
+}
+
+// report queries every node in parallel and evaluates the results
+func (n *Network) report(clients []*thorclient.Client, block uint32, expectedPeers map[string][]string) *HealthReport {
+	// Peers report each other by p2p node ID
+	nodeIDs := make(map[string]string, len(n.Nodes))
+	for _, node := range n.Nodes {
+		nodeIDs[node.PeerID()] = node.GetID()
+	}
+
+	report := &HealthReport{
+		Block: block,
+		Time:  time.Now(),
+		Nodes: make([]NodeHealth, len(n.Nodes)),
+	}
+
+	var wg sync.WaitGroup
+	for i, node := range n.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Nodes[i] = nodeHealth(node.GetID(), clients[i], block, nodeIDs)
+		}()
+	}
+	wg.Wait()
+
+	// Public networks peer with nodes outside the network, their peers are not checked
+	checkPeers := !n.hasPublicNetworkNodes() && len(n.Nodes) > 1
+	for i := range report.Nodes {
+		health := &report.Nodes[i]
+		if checkPeers {
+			health.ExpectedPeers = slices.Sorted(slices.Values(expectedPeers[health.ID]))
+		}
+
+		switch {
+		case !health.Reachable:
+			report.Problems = append(report.Problems, fmt.Sprintf("node %s is unreachable: %s", health.ID, health.Error))
+		case health.Block == nil:
+			report.Problems = append(report.Problems, fmt.Sprintf("node %s has not reached block %d, best block is %d", health.ID, block, health.Best.Number))
+		}
+		if health.Reachable && checkPeers && !slices.Equal(health.Peers, health.ExpectedPeers) {
+			report.Problems = append(report.Problems, fmt.Sprintf("node %s has peers %v, expected %v", health.ID, health.Peers, health.ExpectedPeers))
+		}
+	}
+
+	// Nodes that can reach each other must agree on the block, partition groups are expected to diverge
+	for _, group := range n.partitionGroups() {
+		report.Problems = append(report.Problems, blockMismatches(report, group, block)...)
+	}
+
+	report.Healthy = len(report.Problems) == 0
+	return report
+}
+
+// nodeHealth queries the state of a single node
+func nodeHealth(id string, client *thorclient.Client, block uint32, nodeIDs map[string]string) NodeHealth {
+	health := NodeHealth{ID: id, Peers: []string{}}
+
+	best, err := client.Block("best")
+	if err == nil && best == nil {
+		err = errors.New("no best block")
+	}
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Reachable = true
+	health.Best = blockRef(best)
+	metrics.SetBlockHeight(id, best.Number)
+
+	if finalized, err := client.Block("finalized"); err == nil && finalized != nil {
+		health.Finalized = blockRef(finalized)
+	}
+	if blk, err := client.Block(fmt.Sprintf("%d", block)); err == nil && blk != nil {
+		health.Block = blockRef(blk)
+	}
+
+	peers, err := client.Peers()
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to get peers: %v", err)
+		return health
+	}
+	for _, peer := range peers {
+		peerID, ok := nodeIDs[peer.PeerID]
+		if !ok {
+			peerID = peer.PeerID
+		}
+		health.Peers = append(health.Peers, peerID)
+	}
+	slices.Sort(health.Peers)
+	health.PeerCount = len(health.Peers)
+	return health
+}
+
+// blockMismatches compares the block of every node of the group with the first node that has it
+func blockMismatches(report *HealthReport, group []node.Config, block uint32) []string {
+	var (
+		problems []string
+		baseID   string
+		base     *BlockRef
+	)
+	for _, nodeCfg := range group {
+		health := report.Node(nodeCfg.GetID())
+		if health == nil || health.Block == nil {
+			continue
+		}
+		if base == nil {
+			baseID, base = health.ID, health.Block
+			continue
+		}
+		if health.Block.ID != base.ID {
+			problems = append(problems, fmt.Sprintf(
+				"block hash mismatch at height %d - node %s has %s (timestamp %d), expected %s (timestamp %d) from node %s",
+				block, health.ID, health.Block.ID, health.Block.Timestamp, base.ID, base.Timestamp, baseID,
+			))
+		}
+	}
+	return problems
+}
+
+func blockRef(blk *api.JSONCollapsedBlock) *BlockRef {
+	return &BlockRef{Number: blk.Number, ID: blk.ID.String(), Timestamp: blk.Timestamp}
+}
@@ -0,0 +1,130 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Link shaping presets, see LinkPreset
+const (
+	// LinkLAN models nodes in the same datacenter
+	LinkLAN = "lan"
+	// LinkCrossRegion models nodes in different regions of the same continent
+	LinkCrossRegion = "cross-region"
+	// LinkIntercontinental models nodes on different continents
+	LinkIntercontinental = "intercontinental"
+	// LinkLossy models a congested link dropping packets
+	LinkLossy = "lossy"
+	// LinkConstrained models a slow, low bandwidth link
+	LinkConstrained = "constrained"
+)
+
+// LinkProfile describes the traffic a node sends over a link, zero values leave the property unshaped
+type LinkProfile struct {
+	Delay         time.Duration `json:"delay,omitempty"`
+	Jitter        time.Duration `json:"jitter,omitempty"`        // random variation of the delay, needs a delay
+	Loss          float64       `json:"loss,omitempty"`          // percentage of packets dropped
+	BandwidthKbit uint64        `json:"bandwidthKbit,omitempty"` // rate limit in kbit/s
+}
+
+// LinkShape applies a profile to the links of a node, in both directions.
+// With an empty Peer it shapes the links to every other node, otherwise only the link to Peer.
+type LinkShape struct {
+	Node    string      `json:"node"`
+	Peer    string      `json:"peer,omitempty"`
+	Profile LinkProfile `json:"profile"`
+}
+
+// Links maps each node to the profile of the traffic it sends to each peer
+type Links map[string]map[string]LinkProfile
+
+// LinkPreset returns the profile of a named preset such as LinkCrossRegion
+func LinkPreset(name string) (LinkProfile, error) {
+	switch name {
+	case LinkLAN:
+		return LinkProfile{Delay: time.Millisecond, Jitter: 200 * time.Microsecond}, nil
+	case LinkCrossRegion:
+		return LinkProfile{Delay: 40 * time.Millisecond, Jitter: 5 * time.Millisecond, Loss: 0.1}, nil
+	case LinkIntercontinental:
+		return LinkProfile{Delay: 120 * time.Millisecond, Jitter: 15 * time.Millisecond, Loss: 0.5}, nil
+	case LinkLossy:
+		return LinkProfile{Delay: 20 * time.Millisecond, Jitter: 5 * time.Millisecond, Loss: 5}, nil
+	case LinkConstrained:
+		return LinkProfile{Delay: 50 * time.Millisecond, Jitter: 10 * time.Millisecond, Loss: 1, BandwidthKbit: 1024}, nil
+	default:
+		return LinkProfile{}, fmt.Errorf("unknown link preset %q", name)
+	}
+}
+
+// Validate checks the profile values are in range
+func (p LinkProfile) Validate() error {
+	if p.Delay < 0 || p.Jitter < 0 {
+		return fmt.Errorf("delay and jitter cannot be negative")
+	}
+	if p.Jitter > 0 && p.Delay == 0 {
+		return fmt.Errorf("jitter needs a delay")
+	}
+	if p.Loss < 0 || p.Loss > 100 {
+		return fmt.Errorf("loss must be a percentage, got %v", p.Loss)
+	}
+	return nil
+}
+
+// IsZero reports whether the profile leaves the link unshaped
+func (p LinkProfile) IsZero() bool {
+	return p == LinkProfile{}
+}
+
+// Links resolves link shapes into the profile of every shaped node to peer direction.
+// Shapes apply in order, a later shape overrides earlier ones on the links they share.
+func (n *Network) Links(shapes ...LinkShape) (Links, error) {
+	ids := make([]string, 0, len(n.Nodes))
+	for _, node := range n.Nodes {
+		ids = append(ids, node.GetID())
+	}
+
+	links := make(Links)
+	set := func(from, to string, profile LinkProfile) {
+		if links[from] == nil {
+			links[from] = make(map[string]LinkProfile)
+		}
+		links[from][to] = profile
+	}
+
+	for _, shape := range shapes {
+		if !slices.Contains(ids, shape.Node) {
+			return nil, fmt.Errorf("link shape references unknown node %s", shape.Node)
+		}
+		if shape.Peer != "" && !slices.Contains(ids, shape.Peer) {
+			return nil, fmt.Errorf("link shape references unknown node %s", shape.Peer)
+		}
+		if shape.Peer == shape.Node {
+			return nil, fmt.Errorf("node %s cannot shape a link to itself", shape.Node)
+		}
+		if err := shape.Profile.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid link profile for node %s: %w", shape.Node, err)
+		}
+
+		for _, peer := range ids {
+			if peer == shape.Node || (shape.Peer != "" && peer != shape.Peer) {
+				continue
+			}
+			set(shape.Node, peer, shape.Profile)
+			set(peer, shape.Node, shape.Profile)
+		}
+	}
+
+	// Zero profiles clear earlier shapes
+	for from, peers := range links {
+		for to, profile := range peers {
+			if profile.IsZero() {
+				delete(peers, to)
+			}
+		}
+		if len(peers) == 0 {
+			delete(links, from)
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/network/node"
+)
+
+func TestNetworkLinks(t *testing.T) {
+	n := &Network{Nodes: []node.Config{&node.BaseNode{ID: "a"}, &node.BaseNode{ID: "b"}, &node.BaseNode{ID: "c"}}}
+
+	crossRegion, err := LinkPreset(LinkCrossRegion)
+	require.NoError(t, err)
+	lan, err := LinkPreset(LinkLAN)
+	require.NoError(t, err)
+
+	// a sits in another region, but its link to b is local; c's link to b is cleared by a zero profile
+	links, err := n.Links(
+		LinkShape{Node: "a", Profile: crossRegion},
+		LinkShape{Node: "a", Peer: "b", Profile: lan},
+		LinkShape{Node: "c", Peer: "b", Profile: LinkProfile{}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, Links{
+		"a": {"b": lan, "c": crossRegion},
+		"b": {"a": lan},
+		"c": {"a": crossRegion},
+	}, links)
+
+	links, err = n.Links()
+	require.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestNetworkLinksErrors(t *testing.T) {
+	n := &Network{Nodes: []node.Config{&node.BaseNode{ID: "a"}, &node.BaseNode{ID: "b"}}}
+
+	for _, tc := range []struct {
+		shape LinkShape
+		err   string
+	}{
+		{LinkShape{Node: "x"}, "unknown node x"},
+		{LinkShape{Node: "a", Peer: "x"}, "unknown node x"},
+		{LinkShape{Node: "a", Peer: "a"}, "cannot shape a link to itself"},
+		{LinkShape{Node: "a", Profile: LinkProfile{Jitter: time.Millisecond}}, "jitter needs a delay"},
+		{LinkShape{Node: "a", Profile: LinkProfile{Loss: 101}}, "loss must be a percentage"},
+	} {
+		_, err := n.Links(tc.shape)
+		assert.ErrorContains(t, err, tc.err)
+	}
+
+	_, err := LinkPreset("moon")
+	assert.ErrorContains(t, err, `unknown link preset "moon"`)
+}
@@ -3,11 +3,10 @@ package network
 import (
 	"encoding/json"
 	"fmt"
-	"time"
+	"sync"
 
 	"github.com/vechain/networkhub/network/node"
 	"github.com/vechain/networkhub/thorbuilder"
-	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thor/v2/thorclient"
 )
 
@@ -23,6 +22,23 @@ type Network struct {
 	Nodes       []node.Config       `json:"nodes"`
 	BaseID      string              `json:"baseid"`
 	ThorBuilder *thorbuilder.Config `json:"thorBuilder,omitempty"`
+	Topology    *Topology           `json:"topology,omitempty"` // p2p topology, a full mesh when nil
+	// SkipGenesisCheck starts the nodes without checking their genesis first, for tests of broken genesis
+	SkipGenesisCheck bool `json:"skipGenesisCheck,omitempty"`
+
+	// mu guards the state of the running network below, set while health checks and monitors read it
+	mu sync.RWMutex
+	// partition holds the node groups of a running partition, set by the environment, nil when healed
+	partition [][]string
+	// lastHealth holds the last report produced by HealthReport or HealthCheck, nil before the first check
+	lastHealth *HealthReport
+}
+
+// LastHealth returns the last report produced by HealthReport or HealthCheck, nil before the first check
+func (n *Network) LastHealth() *HealthReport {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.lastHealth
 }
 
 type Builder struct {
@@ -41,6 +57,8 @@ func WithJSON(s string) BuilderOptionsFunc {
 		n.Nodes = network.Nodes
 		n.BaseID = network.BaseID
 		n.Environment = network.Environment
+		n.Topology = network.Topology
+		n.SkipGenesisCheck = network.SkipGenesisCheck
 		return nil
 	}
 }
@@ -63,128 +81,21 @@ func UnmarshalNode(data []byte) (node.Config, error) {
 	return nodeType, nil
 }
 
-func (n *Network) HealthCheck(block uint32, timeout time.Duration) error {
-	if len(n.Nodes) == 0 {
-		return fmt.Errorf("no nodes defined in the network")
-	}
-
-	// Phase A: Node Health - Check if each node is healthy and can fetch blocks
-	if err := n.checkNodeHealth(block, timeout); err != nil {
-		return fmt.Errorf("node health check failed: %w", err)
-	}
-
-	// Phase B: Peer Connectivity - Check if nodes are properly connected (skip for public networks)
-	if err := n.checkPeerConnectivity(timeout); err != nil {
-		return fmt.Errorf("peer connectivity check failed: %w", err)
-	}
-
-	// Phase C: Block Consistency - Check if all nodes have the same block hash
-	if err := n.checkBlockConsistency(block, timeout); err != nil {
-		return fmt.Errorf("block consistency check failed: %w", err)
-	}
-
-	return nil
-}
-
-// checkNodeHealth verifies each node can fetch the specified block
-func (n *Network) checkNodeHealth(block uint32, timeout time.Duration) error {
+// BlockTimestamps returns the timestamp each node reports for the block at revision (a number, ID or "best"),
+// to observe the effect of clock skew on block production
+func (n *Network) BlockTimestamps(revision string) (map[string]uint64, error) {
+	timestamps := make(map[string]uint64, len(n.Nodes))
 	for _, node := range n.Nodes {
-		if err := node.HealthCheck(block, timeout); err != nil {
-			return fmt.Errorf("node %s health check failed: %w", node.GetID(), err)
-		}
-	}
-	return nil
-}
-
-// checkPeerConnectivity verifies all nodes are connected to expected number of peers
-func (n *Network) checkPeerConnectivity(timeout time.Duration) error {
-	// Skip peer connectivity check for public networks
-	if n.hasPublicNetworkNodes() {
-		return nil
-	}
-
-	expectedPeerCount := len(n.Nodes) - 1
-	if expectedPeerCount <= 0 {
-		return nil // Single node network or empty - no peer connectivity to check
-	}
-
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-time.After(time.Until(deadline)):
-			return fmt.Errorf("timeout waiting for peer connectivity - expected %d peers per node", expectedPeerCount)
-		case <-ticker.C:
-			allConnected := true
-			for _, node := range n.Nodes {
-				client := thorclient.New(node.GetHTTPAddr())
-				peers, err := client.Peers()
-				if err != nil {
-					return fmt.Errorf("failed to get peers for node %s: %w", node.GetID(), err)
-				}
-				if len(peers) != expectedPeerCount {
-					allConnected = false
-					break
-				}
-			}
-
-			if allConnected {
-				return nil
-			}
-
-			// Continue polling
-			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting for peer connectivity - expected %d peers per node", expectedPeerCount)
-			}
+		blk, err := thorclient.New(node.GetHTTPAddr()).Block(revision)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %s from node %s: %w", revision, node.GetID(), err)
 		}
-	}
-}
-
-// checkBlockConsistency verifies all nodes return the same block hash, retrying until timeout
-func (n *Network) checkBlockConsistency(block uint32, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	var lastErr error
-	for {
-		select {
-		case <-time.After(time.Until(deadline)):
-			if lastErr != nil {
-				return lastErr
-			}
-			return fmt.Errorf("timeout waiting for block consistency at height %d", block)
-		case <-ticker.C:
-			var baseBlk *api.JSONCollapsedBlock
-			lastErr = nil
-			for _, node := range n.Nodes {
-				client := thorclient.New(node.GetHTTPAddr())
-				nodeBlk, err := client.Block(fmt.Sprintf("%d", block))
-				if err != nil {
-					lastErr = fmt.Errorf("failed to get block %d from node %s: %w", block, node.GetID(), err)
-					break
-				}
-				if baseBlk == nil {
-					baseBlk = nodeBlk
-				} else if baseBlk.ID != nodeBlk.ID {
-					lastErr = fmt.Errorf(
-						"block hash mismatch at height %d - node %s has %s, expected %s",
-						block, node.GetID(), nodeBlk.ID.String(), baseBlk.ID.String(),
-					)
-					break
-				}
-			}
-			if lastErr == nil {
-				return nil
-			}
-
-			if time.Now().After(deadline) {
-				return lastErr
-			}
+		if blk == nil {
+			return nil, fmt.Errorf("node %s has no block %s", node.GetID(), revision)
 		}
+		timestamps[node.GetID()] = blk.Timestamp
 	}
+	return timestamps, nil
 }
 
 // IsPublicNetwork determines if this network is a public network (mainnet/testnet)
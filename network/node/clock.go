@@ -0,0 +1,22 @@
+package node
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkew offsets and drifts the wall clock of a node. Only environments listing the clock-skew capability
+// apply it. Thor reads the clock through the vDSO, which LD_PRELOAD libraries such as libfaketime cannot
+// intercept, so environments running thor need it built with thorbuilder's SkewableClock.
+type ClockSkew struct {
+	Offset time.Duration `json:"offset,omitempty"` // how far ahead the clock is, negative values run behind
+	Drift  float64       `json:"drift,omitempty"`  // seconds the clock gains per second, negative values lose time
+}
+
+// Validate checks the drift leaves the clock running forward
+func (c *ClockSkew) Validate() error {
+	if c.Drift <= -1 {
+		return fmt.Errorf("clock drift must be greater than -1, got %v", c.Drift)
+	}
+	return nil
+}
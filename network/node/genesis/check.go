@@ -0,0 +1,371 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+var (
+	// configMu guards thor's process-wide configuration, which offline builds and in-process nodes apply
+	configMu sync.Mutex
+	// configHolders counts the in-process nodes running on heldConfig
+	configHolders int
+	// heldConfig is the configuration in-process nodes run on while configHolders is positive
+	heldConfig thor.Config
+	// unheldConfig is the configuration restored once the last in-process node released heldConfig
+	unheldConfig thor.Config
+	// defaultConfig is thor's configuration before any genesis is applied
+	defaultConfig = currentConfig()
+)
+
+// HoldConfig applies the genesis config to thor's process-wide configuration for an in-process node, and
+// returns the release to call once the node stopped. In-process nodes can only run together on the same
+// config, a config other than the held one is rejected. The last release restores the previous config.
+func HoldConfig(genConfig *thor.Config) (release func(), err error) {
+	config, err := configFor(genConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	if err := checkHeld(config); err != nil {
+		return nil, err
+	}
+	if configHolders == 0 {
+		if thor.IsConfigLocked() {
+			if !sameConfig(currentConfig(), config) {
+				return nil, fmt.Errorf("thor configuration is locked in this process, the genesis config cannot be applied")
+			}
+		} else {
+			unheldConfig = currentConfig()
+			thor.SetConfig(config)
+		}
+		heldConfig = config
+	}
+	configHolders++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			configMu.Lock()
+			defer configMu.Unlock()
+			configHolders--
+			if configHolders == 0 && !thor.IsConfigLocked() {
+				thor.SetConfig(unheldConfig)
+			}
+		})
+	}, nil
+}
+
+// CheckHeldConfig returns an error when in-process nodes hold a configuration other than the genesis config
+func CheckHeldConfig(genConfig *thor.Config) error {
+	config, err := configFor(genConfig)
+	if err != nil {
+		return err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	return checkHeld(config)
+}
+
+// checkHeld compares the config with the held one, configMu must be locked
+func checkHeld(config thor.Config) error {
+	if configHolders > 0 && !sameConfig(heldConfig, config) {
+		held, _ := json.Marshal(heldConfig)
+		return fmt.Errorf("in-process nodes are running on another thor configuration, which is process-wide: %s", held)
+	}
+	return nil
+}
+
+// ToThor converts the genesis into thor's genesis the same way the thor binary parses a genesis file
+func ToThor(customGenesis *CustomGenesis) (*thorgenesis.CustomGenesis, *thor.ForkConfig, error) {
+	if customGenesis == nil {
+		return nil, nil, fmt.Errorf("genesis cannot be nil")
+	}
+
+	// Forks and config of newer thor versions are unknown to networkhub's thor and left out
+	withoutAdditional := *customGenesis
+	if customGenesis.ForkConfig != nil && len(customGenesis.ForkConfig.AdditionalFields) > 0 {
+		forkConfig := *customGenesis.ForkConfig
+		forkConfig.AdditionalFields = nil
+		withoutAdditional.ForkConfig = &forkConfig
+	}
+	if customGenesis.Config != nil && len(customGenesis.Config.AdditionalFields) > 0 {
+		config := *customGenesis.Config
+		config.AdditionalFields = nil
+		withoutAdditional.Config = &config
+	}
+	customGenesis = &withoutAdditional
+
+	data, err := Marshal(customGenesis)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to marshal genesis: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	forkConfig := thor.NoFork
+	var gen thorgenesis.CustomGenesis
+	gen.ForkConfig = &forkConfig
+	if err := decoder.Decode(&gen); err != nil {
+		return nil, nil, fmt.Errorf("unable to decode genesis: %w", err)
+	}
+	return &gen, &forkConfig, nil
+}
+
+// Check builds the genesis offline like thor does and returns its ID. It also checks the genesis
+// lets the nodes produce blocks: keys maps node IDs to their master keys, each must be the master of
+// a staker or authority. Nodes without a key are skipped.
+// The ID is left zero when in-process nodes run on another config, thor cannot build the genesis then.
+func Check(customGenesis *CustomGenesis, keys map[string]string) (thor.Bytes32, error) {
+	gen, forkConfig, err := ToThor(customGenesis)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+
+	// Check a copy, NewCustomNet fills in defaults
+	id, config, err := build(*gen)
+	if err != nil {
+		return thor.Bytes32{}, fmt.Errorf("thor rejects the genesis: %w", err)
+	}
+
+	var problems []string
+	problems = append(problems, checkValidators(gen, forkConfig, keys)...)
+	problems = append(problems, checkAuthority(gen, forkConfig, config, keys)...)
+	problems = append(problems, checkStakingPeriods(config)...)
+	if len(problems) > 0 {
+		return id, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return id, nil
+}
+
+// build computes the genesis ID with thor and returns the effective thor config. Thor reads its
+// process-wide configuration while building, so it is set to the effective config and restored afterwards.
+// While in-process nodes hold the configuration it is left untouched: the ID is built when the held config
+// is the effective one, and left zero otherwise.
+func build(gen thorgenesis.CustomGenesis) (id thor.Bytes32, config thor.Config, err error) {
+	config, err = configFor(gen.Config)
+	if err != nil {
+		return id, config, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	switch {
+	case configHolders > 0:
+		if !sameConfig(heldConfig, config) {
+			slog.Warn("Genesis ID not checked, in-process nodes are running on another thor configuration")
+			return id, config, nil
+		}
+	case thor.IsConfigLocked():
+		if !sameConfig(currentConfig(), config) {
+			return id, config, fmt.Errorf("thor configuration is locked in this process, the genesis config cannot be applied")
+		}
+	default:
+		previous := currentConfig()
+		thor.SetConfig(config)
+		defer thor.SetConfig(previous)
+	}
+	// The config is applied above
+	gen.Config = nil
+
+	// NewCustomNet panics when the genesis state cannot be built, e.g. an endorser cannot pay its stake
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	built, err := thorgenesis.NewCustomNet(&gen)
+	if err != nil {
+		return id, config, err
+	}
+	return built.ID(), config, nil
+}
+
+// configFor validates the genesis config like NewCustomNet does and returns the effective config
+func configFor(genConfig *thor.Config) (thor.Config, error) {
+	if genConfig != nil {
+		// value of 0 does not update thor config
+		if genConfig.BlockInterval == 1 {
+			return thor.Config{}, errors.New("BlockInterval can not be zero or one")
+		}
+		if genConfig.EpochLength == 1 {
+			return thor.Config{}, errors.New("EpochLength can not be zero or one")
+		}
+	}
+	return effectiveConfig(genConfig), nil
+}
+
+// effectiveConfig returns thor's default configuration overridden by the genesis config,
+// following the rules of thor.SetConfig: zero values keep the default
+func effectiveConfig(genConfig *thor.Config) thor.Config {
+	config := defaultConfig
+	hayabusaTP := *defaultConfig.HayabusaTP
+	config.HayabusaTP = &hayabusaTP
+	if genConfig == nil {
+		return config
+	}
+
+	for _, field := range []struct {
+		value    uint32
+		override *uint32
+	}{
+		{genConfig.EpochLength, &config.EpochLength},
+		{genConfig.SeederInterval, &config.SeederInterval},
+		{genConfig.ValidatorEvictionThreshold, &config.ValidatorEvictionThreshold},
+		{genConfig.EvictionCheckInterval, &config.EvictionCheckInterval},
+		{genConfig.LowStakingPeriod, &config.LowStakingPeriod},
+		{genConfig.MediumStakingPeriod, &config.MediumStakingPeriod},
+		{genConfig.HighStakingPeriod, &config.HighStakingPeriod},
+		{genConfig.CooldownPeriod, &config.CooldownPeriod},
+	} {
+		if field.value != 0 {
+			*field.override = field.value
+		}
+	}
+	if genConfig.BlockInterval != 0 {
+		config.BlockInterval = genConfig.BlockInterval
+	}
+	if genConfig.HayabusaTP != nil {
+		hayabusaTP = *genConfig.HayabusaTP
+	}
+	return config
+}
+
+// sameConfig reports whether two complete configurations are equal
+func sameConfig(a, b thor.Config) bool {
+	aTP, bTP := *a.HayabusaTP, *b.HayabusaTP
+	a.HayabusaTP, b.HayabusaTP = nil, nil
+	return a == b && aTP == bTP
+}
+
+// checkValidators checks every keyed node is the master of a validator, and that every validator node can propose
+func checkValidators(gen *thorgenesis.CustomGenesis, forkConfig *thor.ForkConfig, keys map[string]string) []string {
+	var problems []string
+
+	masters := make(map[thor.Address]bool)
+	for _, authority := range gen.Authority {
+		masters[authority.MasterAddress] = true
+	}
+	// Thor only registers the stakers of a genesis that starts on Hayabusa
+	if len(gen.Stakers) > 0 && forkConfig.HAYABUSA != 0 {
+		problems = append(problems, fmt.Sprintf("stakers are only registered when the HAYABUSA fork is at block 0, it is at %d", forkConfig.HAYABUSA))
+	} else {
+		for _, staker := range gen.Stakers {
+			masters[staker.Master] = true
+		}
+	}
+
+	validators := 0
+	for _, id := range slices.Sorted(maps.Keys(keys)) {
+		if keys[id] == "" {
+			continue
+		}
+		privKey, err := crypto.HexToECDSA(keys[id])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("node %s has an invalid key: %v", id, err))
+			continue
+		}
+		master := thor.Address(crypto.PubkeyToAddress(privKey.PublicKey))
+		if !masters[master] {
+			problems = append(problems, fmt.Sprintf("node %s key maps to %s, which is not the master of a staker or authority", id, master))
+			continue
+		}
+		validators++
+	}
+
+	if mbp := gen.Params.MaxBlockProposers; mbp != nil && *mbp < uint64(validators) {
+		problems = append(problems, fmt.Sprintf("maxBlockProposers %d is below the %d validator nodes, some would never propose", *mbp, validators))
+	}
+	return problems
+}
+
+// checkAuthority checks, while the network runs PoA, that every authority has a node and its endorser holds
+// the proposer endorsement. Authorities without a node would leave their block slots empty.
+func checkAuthority(gen *thorgenesis.CustomGenesis, forkConfig *thor.ForkConfig, config thor.Config, keys map[string]string) []string {
+	if forkConfig.HAYABUSA == 0 && *config.HayabusaTP == 0 {
+		return nil
+	}
+
+	var problems []string
+	nodeMasters := make(map[thor.Address]bool)
+	for _, key := range keys {
+		if privKey, err := crypto.HexToECDSA(key); err == nil {
+			nodeMasters[thor.Address(crypto.PubkeyToAddress(privKey.PublicKey))] = true
+		}
+	}
+
+	endorsement := thor.InitialProposerEndorsement
+	if gen.Params.ProposerEndorsement != nil {
+		endorsement = (*big.Int)(gen.Params.ProposerEndorsement)
+	}
+
+	// Later accounts override earlier ones, like in thor
+	balances := make(map[thor.Address]*big.Int)
+	for _, account := range gen.Accounts {
+		balances[account.Address] = (*big.Int)(account.Balance)
+	}
+
+	for _, authority := range gen.Authority {
+		if !nodeMasters[authority.MasterAddress] {
+			problems = append(problems, fmt.Sprintf("authority %s has no node in the network", authority.MasterAddress))
+		}
+
+		balance := balances[authority.EndorsorAddress]
+		if balance == nil {
+			balance = new(big.Int)
+		}
+		if balance.Cmp(endorsement) < 0 {
+			problems = append(problems, fmt.Sprintf(
+				"endorser %s of authority %s holds %s, below the proposer endorsement %s",
+				authority.EndorsorAddress, authority.MasterAddress, balance, endorsement,
+			))
+		}
+	}
+	return problems
+}
+
+// checkStakingPeriods checks the staking periods are distinct and increasing, so stakers can tell them apart
+func checkStakingPeriods(config thor.Config) []string {
+	if config.LowStakingPeriod < config.MediumStakingPeriod && config.MediumStakingPeriod < config.HighStakingPeriod {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"staking periods must increase from low to high, got low %d, medium %d and high %d",
+		config.LowStakingPeriod, config.MediumStakingPeriod, config.HighStakingPeriod,
+	)}
+}
+
+// currentConfig returns thor's process-wide configuration
+func currentConfig() thor.Config {
+	hayabusaTP := thor.HayabusaTP()
+	return thor.Config{
+		BlockInterval:              thor.BlockInterval(),
+		EpochLength:                thor.EpochLength(),
+		SeederInterval:             thor.SeederInterval(),
+		ValidatorEvictionThreshold: thor.ValidatorEvictionThreshold(),
+		EvictionCheckInterval:      thor.EvictionCheckInterval(),
+		LowStakingPeriod:           thor.LowStakingPeriod(),
+		MediumStakingPeriod:        thor.MediumStakingPeriod(),
+		HighStakingPeriod:          thor.HighStakingPeriod(),
+		CooldownPeriod:             thor.CooldownPeriod(),
+		HayabusaTP:                 &hayabusaTP,
+	}
+}
@@ -1,5 +1,11 @@
 package genesis
 
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
 type Config struct {
 	BlockInterval              uint64  `json:"blockInterval"`
 	EpochLength                uint32  `json:"epochLength"`
@@ -11,4 +17,40 @@ type Config struct {
 	HighStakingPeriod          uint32  `json:"highStakingPeriod"`
 	CooldownPeriod             uint32  `json:"cooldownPeriod"`
 	HayabusaTP                 *uint32 `json:"hayabusaTP"`
+	// AdditionalFields are config keys of newer thor versions, written to the genesis as they are
+	AdditionalFields map[string]interface{} `json:"additionalFields,omitempty"`
+}
+
+// UnmarshalJSON keeps the keys Config does not know in AdditionalFields, so they pass through to the genesis file
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type Alias Config
+	if err := json.Unmarshal(data, (*Alias)(c)); err != nil {
+		return err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if key == "additionalFields" || slices.Contains(CurrentFormat.Known["config"], key) {
+			continue
+		}
+		if err := c.AddField(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddField sets a config key unknown to networkhub's thor
+func (c *Config) AddField(key string, value interface{}) error {
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	if c.AdditionalFields == nil {
+		c.AdditionalFields = make(map[string]interface{})
+	}
+	c.AdditionalFields[key] = value
+	return nil
 }
@@ -1,6 +1,7 @@
 package genesis
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -18,20 +19,22 @@ type CustomGenesis struct {
 }
 
 func HandleAdditionalFields(raw *map[string]interface{}) {
-	if forkConfig, ok := (*raw)["forkConfig"].(map[string]interface{}); ok {
+	for _, section := range []string{"forkConfig", "config"} {
+		fields, ok := (*raw)[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
 		// Handle AdditionalFields
-		if additionalFields, ok := forkConfig["additionalFields"].(map[string]interface{}); ok {
+		if additionalFields, ok := fields["additionalFields"].(map[string]interface{}); ok {
 			for key, value := range additionalFields {
-				if num, ok := value.(float64); ok { // JSON numbers are float64 by default
-					forkConfig[key] = uint32(num)
-					delete(additionalFields, key)
-				}
-				if len(additionalFields) == 0 {
-					delete(forkConfig, "additionalFields")
+				if num, ok := value.(float64); ok && section == "forkConfig" { // JSON numbers are float64 by default
+					value = uint32(num)
 				}
+				fields[key] = value
 			}
-			(*raw)["forkConfig"] = forkConfig
+			delete(fields, "additionalFields")
 		}
+		(*raw)[section] = fields
 	}
 }
 
@@ -41,7 +44,7 @@ func Marshal(customGenesis *CustomGenesis) ([]byte, error) {
 		return nil, err
 	}
 	var raw map[string]interface{}
-	if err = json.Unmarshal(data, &raw); err != nil {
+	if err = decodeNumbers(data, &raw); err != nil {
 		return nil, err
 	}
 
@@ -55,6 +58,14 @@ func Marshal(customGenesis *CustomGenesis) ([]byte, error) {
 	return modifiedData, nil
 }
 
+// decodeNumbers unmarshals JSON keeping numbers as json.Number, so uint64 fields such as the launch time
+// and gas limit survive a round trip without float64 rounding
+func decodeNumbers(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
 type CustomGenesisForkConfig struct {
 	thor.ForkConfig
 	AdditionalFields map[string]uint32 `json:"additionalFields,omitempty"`
@@ -0,0 +1,165 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Sections of a genesis file that Format lists fields for, "" is the top level
+var sections = []string{"", "params", "forkConfig", "config"}
+
+// Format describes the genesis file a thor release accepts
+type Format struct {
+	// Version is the first thor release that accepts this format
+	Version string
+	// Known lists the fields the release knows per section: "" for the top level, "params", "forkConfig" and "config"
+	Known map[string][]string
+}
+
+// CurrentFormat is the genesis format of the thor version networkhub is built with
+var CurrentFormat = Format{
+	Version: "v2.4.0",
+	Known: map[string][]string{
+		"":           {"launchTime", "gaslimit", "extraData", "accounts", "authority", "stakers", "params", "executor", "forkConfig", "config"},
+		"params":     {"rewardRatio", "baseGasPrice", "proposerEndorsement", "executorAddress", "maxBlockProposers", "delegatorContract", "curveFactor", "stakerSwitches", "validatorRewardPercentage"},
+		"forkConfig": {"VIP191", "ETH_CONST", "BLOCKLIST", "ETH_IST", "VIP214", "FINALITY", "HAYABUSA", "GALACTICA"},
+		"config":     {"blockInterval", "epochLength", "seederInterval", "validatorEvictionThreshold", "evictionCheckInterval", "lowStakingPeriod", "mediumStakingPeriod", "highStakingPeriod", "cooldownPeriod", "hayabusaTP"},
+	},
+}
+
+// formats are the known genesis formats, newest first
+var formats = []Format{
+	CurrentFormat,
+	{
+		// Galactica, before stakers and the thor config
+		Version: "v2.3.0",
+		Known: map[string][]string{
+			"":           {"launchTime", "gaslimit", "extraData", "accounts", "authority", "params", "executor", "forkConfig"},
+			"params":     {"rewardRatio", "baseGasPrice", "proposerEndorsement", "executorAddress", "maxBlockProposers"},
+			"forkConfig": {"VIP191", "ETH_CONST", "BLOCKLIST", "ETH_IST", "VIP214", "FINALITY", "GALACTICA"},
+		},
+	},
+	{
+		// Finality
+		Version: "v2.1.0",
+		Known: map[string][]string{
+			"":           {"launchTime", "gaslimit", "extraData", "accounts", "authority", "params", "executor", "forkConfig"},
+			"params":     {"rewardRatio", "baseGasPrice", "proposerEndorsement", "executorAddress", "maxBlockProposers"},
+			"forkConfig": {"VIP191", "ETH_CONST", "BLOCKLIST", "ETH_IST", "VIP214", "FINALITY"},
+		},
+	},
+	{
+		Version: "v2.0.0",
+		Known: map[string][]string{
+			"":           {"launchTime", "gaslimit", "extraData", "accounts", "authority", "params", "executor", "forkConfig"},
+			"params":     {"rewardRatio", "baseGasPrice", "proposerEndorsement", "executorAddress", "maxBlockProposers"},
+			"forkConfig": {"VIP191", "ETH_CONST", "BLOCKLIST", "ETH_IST", "VIP214"},
+		},
+	},
+}
+
+// FormatFor returns the genesis format of a thor release, such as "v2.3.1". Empty is the thor version networkhub
+// is built with, and releases newer than CurrentFormat use it too.
+func FormatFor(version string) (Format, error) {
+	if version == "" {
+		return CurrentFormat, nil
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return Format{}, fmt.Errorf("thor version %s is not a release version like v2.4.0", strings.TrimPrefix(version, "v"))
+	}
+	for _, format := range formats {
+		if semver.Compare(version, format.Version) >= 0 {
+			return format, nil
+		}
+	}
+	return Format{}, fmt.Errorf("thor %s predates %s, the oldest genesis format networkhub knows", version, formats[len(formats)-1].Version)
+}
+
+// MarshalFor renders the genesis for the given thor release, see FormatFor and Format.Render
+func MarshalFor(customGenesis *CustomGenesis, version string) ([]byte, error) {
+	format, err := FormatFor(version)
+	if err != nil {
+		return nil, err
+	}
+	return format.Render(customGenesis)
+}
+
+// Render marshals the genesis for the release. Fields of networkhub's thor that the release does not know are
+// omitted when unset, and are an error otherwise as the release could not honour them. Other unknown fields,
+// set through AdditionalFields, pass through as they are meant for a newer release.
+func (f Format) Render(customGenesis *CustomGenesis) ([]byte, error) {
+	data, err := Marshal(customGenesis)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := decodeNumbers(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var unsupported []string
+	// Sections are pruned before the top level, a section left empty is unset
+	for _, section := range slices.Backward(sections) {
+		fields := raw
+		if section != "" {
+			nested, ok := raw[section].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fields = nested
+		}
+
+		for key, value := range fields {
+			if !slices.Contains(CurrentFormat.Known[section], key) || slices.Contains(f.Known[section], key) {
+				continue
+			}
+			if isUnset(section, value) {
+				delete(fields, key)
+				continue
+			}
+			// Set fields of a pruned section are reported on their own
+			if _, ok := value.(map[string]interface{}); ok {
+				continue
+			}
+			unsupported = append(unsupported, strings.TrimPrefix(section+"."+key, "."))
+		}
+	}
+	if len(unsupported) > 0 {
+		slices.Sort(unsupported)
+		return nil, fmt.Errorf("thor %s does not support the genesis fields %s", f.Version, strings.Join(unsupported, ", "))
+	}
+
+	return json.Marshal(raw)
+}
+
+// isUnset tells whether the value leaves thor's default in place: null, zero, empty or, for forks, disabled.
+// A fork at block 0 is active from genesis, so only the disabled height leaves a fork unset.
+func isUnset(section string, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case json.Number:
+		if section == "forkConfig" {
+			return v.String() == strconv.FormatUint(math.MaxUint32, 10)
+		}
+		return v.String() == "0"
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package genesis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// render renders the genesis for the version and decodes it back
+func render(t *testing.T, gen *CustomGenesis, version string) map[string]interface{} {
+	data, err := MarshalFor(gen, version)
+	require.NoError(t, err)
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	return raw
+}
+
+func TestMarshalFor(t *testing.T) {
+	forkConfig := thor.NoFork
+	forkConfig.VIP191, forkConfig.ETH_CONST, forkConfig.BLOCKLIST = 0, 0, 0
+	gen := &CustomGenesis{
+		CustomGenesis: &thorgenesis.CustomGenesis{LaunchTime: 1},
+		ForkConfig:    &CustomGenesisForkConfig{ForkConfig: forkConfig},
+		Config:        &Config{},
+	}
+
+	// networkhub's thor gets every field
+	raw := render(t, gen, "")
+	assert.Contains(t, raw["forkConfig"], "HAYABUSA")
+	assert.Contains(t, raw["config"], "hayabusaTP")
+
+	// Releases newer than the known formats use the latest one
+	assert.Equal(t, raw, render(t, gen, "v2.9.0"))
+
+	// Older releases don't see the unset fields they don't know
+	raw = render(t, gen, "2.3.1")
+	assert.NotContains(t, raw, "config")
+	assert.NotContains(t, raw, "stakers")
+	assert.NotContains(t, raw["params"], "delegatorContract")
+	assert.NotContains(t, raw["forkConfig"], "HAYABUSA")
+	assert.Contains(t, raw["forkConfig"], "GALACTICA")
+
+	raw = render(t, gen, "v2.0.5")
+	assert.NotContains(t, raw["forkConfig"], "FINALITY")
+
+	// Numbers are not rounded through float64
+	gen.LaunchTime = 1<<53 + 1
+	data, err := MarshalFor(gen, "v2.3.1")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"launchTime":9007199254740993`)
+
+	// Set fields an older release doesn't know would be silently lost
+	gen.ForkConfig.HAYABUSA = 10
+	gen.Config.BlockInterval = 1
+	gen.Stakers = []thorgenesis.Validator{{}}
+	_, err = MarshalFor(gen, "v2.3.0")
+	assert.EqualError(t, err, "thor v2.3.0 does not support the genesis fields config.blockInterval, forkConfig.HAYABUSA, stakers")
+
+	// A fork at block 0 is active, not unset
+	gen.ForkConfig.HAYABUSA = 0
+	_, err = MarshalFor(gen, "v2.3.0")
+	assert.ErrorContains(t, err, "forkConfig.HAYABUSA")
+
+	_, err = MarshalFor(gen, "v1.7.0")
+	assert.ErrorContains(t, err, "thor v1.7.0 predates v2.0.0")
+	_, err = MarshalFor(gen, "latest")
+	assert.ErrorContains(t, err, "thor version latest is not a release version")
+}
+
+func TestRenderPassThrough(t *testing.T) {
+	gen := &CustomGenesis{
+		CustomGenesis: &thorgenesis.CustomGenesis{},
+		ForkConfig:    NewCustomGenesisForkConfig(thor.SoloFork),
+	}
+
+	// Unknown config keys survive a JSON round trip and reach the genesis file
+	require.NoError(t, json.Unmarshal([]byte(`{"blockInterval":2,"newInterval":5}`), &gen.Config))
+	assert.Equal(t, uint64(2), gen.Config.BlockInterval)
+	assert.Equal(t, map[string]interface{}{"newInterval": float64(5)}, gen.Config.AdditionalFields)
+	require.NoError(t, gen.ForkConfig.AddField("NEW_FORK", 20))
+
+	raw := render(t, gen, "")
+	assert.Equal(t, float64(5), raw["config"].(map[string]interface{})["newInterval"])
+	assert.NotContains(t, raw["config"], "additionalFields")
+	assert.Equal(t, float64(20), raw["forkConfig"].(map[string]interface{})["NEW_FORK"])
+
+	// networkhub's thor builds the genesis without them
+	_, _, err := ToThor(gen)
+	assert.NoError(t, err)
+}
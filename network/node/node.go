@@ -1,6 +1,7 @@
 package node
 
 import (
+	"io"
 	"time"
 
 	"github.com/vechain/networkhub/network/node/genesis"
@@ -8,6 +9,7 @@ import (
 
 type Config interface {
 	Enode(ipAddr string) (string, error)
+	PeerID() string
 	SetExecArtifact(artifact string)
 	GetConfigDir() string
 	SetConfigDir(join string)
@@ -36,9 +38,54 @@ type Config interface {
 	HealthCheck(block uint32, timeout time.Duration) error
 	IsPersistent() bool
 	SetPersistent(bool)
+	GetEnvironment() string
+	SetEnvironment(environment string)
+	GetClockSkew() *ClockSkew
+	SetClockSkew(skew *ClockSkew)
+	IsMetricsEnabled() bool
+	SetMetricsEnabled(enabled bool)
+	GetMetricsAddr() string
+	SetMetricsAddr(addr string)
+	GetMetricsURL() string
+	GetThorVersion() string
+	SetThorVersion(version string)
 }
 
 type Lifecycle interface {
 	Stop() error
 	Start() error
 }
+
+// Operator is implemented by running nodes that support command execution and file transfer.
+// Relative paths are resolved against the node's data directory. Files are transferred as tar
+// archives, matching the Docker archive API.
+type Operator interface {
+	// Exec runs argv on the node and waits for it to finish. An argv[0] of "thor" runs the node's thor binary.
+	Exec(argv []string) (*ExecResult, error)
+	// CopyFrom returns a tar archive of the file or directory at path
+	CopyFrom(path string) (io.ReadCloser, error)
+	// CopyTo extracts the tar archive into the directory at path
+	CopyTo(path string, archive io.Reader) error
+}
+
+// Pauser is implemented by running nodes that can be frozen in place and resumed later,
+// keeping their process, connections and state
+type Pauser interface {
+	Pause() error
+	Resume() error
+}
+
+// Inspector is implemented by running nodes that can report how they were started and what they logged
+type Inspector interface {
+	// CommandLine returns the thor command line the node was started with
+	CommandLine() []string
+	// Logs returns the output of the node since it last started
+	Logs() (io.ReadCloser, error)
+}
+
+// ExecResult holds the output of a command executed on a node
+type ExecResult struct {
+	ExitCode int    `json:"exitCode"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
@@ -29,6 +29,11 @@ type BaseNode struct {
 	Genesis        *genesis.CustomGenesis `json:"genesis"`
 	AdditionalArgs map[string]string      `json:"additionalArgs"`
 	Persistent     bool                   `json:"isPersistent"`
+	Environment    string                 `json:"environment,omitempty"` // overrides the network environment for this node
+	ClockSkew      *ClockSkew             `json:"clockSkew,omitempty"`
+	Metrics        bool                   `json:"metrics,omitempty"`     // enables thor's Prometheus metrics endpoint
+	MetricsAddr    string                 `json:"metricsAddr,omitempty"` // host address of the metrics endpoint, allocated when empty
+	ThorVersion    string                 `json:"thorVersion,omitempty"` // thor release the genesis is rendered for, empty for networkhub's thor
 }
 
 func (b *BaseNode) GetVerbosity() int {
@@ -38,6 +43,59 @@ func (b *BaseNode) GetVerbosity() int {
 	return b.Verbosity
 }
 
+// GetEnvironment returns the environment the node runs in, empty means the network's environment
+func (b *BaseNode) GetEnvironment() string {
+	return b.Environment
+}
+
+func (b *BaseNode) SetEnvironment(environment string) {
+	b.Environment = environment
+}
+
+// GetClockSkew returns the skew applied to the node's clock, nil runs on the host clock
+func (b *BaseNode) GetClockSkew() *ClockSkew {
+	return b.ClockSkew
+}
+
+func (b *BaseNode) SetClockSkew(skew *ClockSkew) {
+	b.ClockSkew = skew
+}
+
+// IsMetricsEnabled reports whether thor serves Prometheus metrics
+func (b *BaseNode) IsMetricsEnabled() bool {
+	return b.Metrics
+}
+
+func (b *BaseNode) SetMetricsEnabled(enabled bool) {
+	b.Metrics = enabled
+}
+
+// GetThorVersion returns the thor release the node's genesis file is rendered for, see genesis.FormatFor
+func (b *BaseNode) GetThorVersion() string {
+	return b.ThorVersion
+}
+
+func (b *BaseNode) SetThorVersion(version string) {
+	b.ThorVersion = version
+}
+
+// GetMetricsAddr returns the host address of the node's metrics endpoint
+func (b *BaseNode) GetMetricsAddr() string {
+	return b.MetricsAddr
+}
+
+func (b *BaseNode) SetMetricsAddr(addr string) {
+	b.MetricsAddr = addr
+}
+
+// GetMetricsURL returns the URL Prometheus scrapes the node's metrics from, empty when metrics are disabled
+func (b *BaseNode) GetMetricsURL() string {
+	if !b.Metrics || b.MetricsAddr == "" {
+		return ""
+	}
+	return "http://" + strings.ReplaceAll(b.MetricsAddr, "0.0.0.0", "127.0.0.1") + "/metrics"
+}
+
 func (b *BaseNode) GetP2PListenPort() int {
 	return b.P2PListenPort
 }
@@ -153,6 +211,16 @@ func (b *BaseNode) Enode(ipAddr string) (string, error) {
 	return fmt.Sprintf("enode://%x@%s:%v", discover.PubkeyID(&privKey.PublicKey).Bytes(), ipAddr, b.P2PListenPort), nil
 }
 
+// PeerID returns the p2p node ID peers report for this node, the hex public key without the 04 prefix.
+// Nodes without a valid key fall back to their ID.
+func (b *BaseNode) PeerID() string {
+	privKey, err := crypto.HexToECDSA(b.Key)
+	if err != nil {
+		return b.ID
+	}
+	return fmt.Sprintf("%x", discover.PubkeyID(&privKey.PublicKey).Bytes())
+}
+
 func (b *BaseNode) HealthCheck(block uint32, timeout time.Duration) error {
 	client := thorclient.New(b.GetHTTPAddr())
 	ticker := time.NewTicker(timeout)
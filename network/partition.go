@@ -0,0 +1,99 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vechain/networkhub/network/node"
+)
+
+// ValidatePartition checks that groups split the network, every node belongs to exactly one group
+func (n *Network) ValidatePartition(groups [][]string) error {
+	if len(groups) < 2 {
+		return fmt.Errorf("a partition needs at least 2 groups, got %d", len(groups))
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range groups {
+		if len(group) == 0 {
+			return fmt.Errorf("partition groups cannot be empty")
+		}
+		for _, id := range group {
+			if !slices.ContainsFunc(n.Nodes, func(nodeCfg node.Config) bool { return nodeCfg.GetID() == id }) {
+				return fmt.Errorf("partition references unknown node %s", id)
+			}
+			if seen[id] {
+				return fmt.Errorf("node %s is in more than one partition group", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	for _, nodeCfg := range n.Nodes {
+		if !seen[nodeCfg.GetID()] {
+			return fmt.Errorf("node %s is not in any partition group", nodeCfg.GetID())
+		}
+	}
+	return nil
+}
+
+// SetPartition records the node groups of a running partition, nil once healed. It is set by the environment.
+func (n *Network) SetPartition(groups [][]string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.partition = cloneGroups(groups)
+}
+
+// Partition returns a copy of the node groups of the running partition, nil when not partitioned
+func (n *Network) Partition() [][]string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return cloneGroups(n.partition)
+}
+
+// IsPartitioned reports whether the network is currently split into partition groups
+func (n *Network) IsPartitioned() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.partition) > 0
+}
+
+// cloneGroups deep copies partition groups
+func cloneGroups(groups [][]string) [][]string {
+	if groups == nil {
+		return nil
+	}
+	clone := make([][]string, len(groups))
+	for i, group := range groups {
+		clone[i] = slices.Clone(group)
+	}
+	return clone
+}
+
+// partitionGroups returns the node groups that can reach each other, the whole network when not partitioned
+func (n *Network) partitionGroups() [][]node.Config {
+	partition := n.Partition()
+	if len(partition) == 0 {
+		return [][]node.Config{n.Nodes}
+	}
+
+	groups := make([][]node.Config, len(partition))
+	for i, ids := range partition {
+		for _, nodeCfg := range n.Nodes {
+			if slices.Contains(ids, nodeCfg.GetID()) {
+				groups[i] = append(groups[i], nodeCfg)
+			}
+		}
+	}
+	return groups
+}
+
+// samePartition reports whether two nodes can reach each other under the partition
+func samePartition(partition [][]string, a, b string) bool {
+	for _, group := range partition {
+		if slices.Contains(group, a) {
+			return slices.Contains(group, b)
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MetricsEndpoints returns the URL of the metrics endpoint of every node with metrics enabled
+func (n *Network) MetricsEndpoints() map[string]string {
+	endpoints := make(map[string]string)
+	for _, node := range n.Nodes {
+		if url := node.GetMetricsURL(); url != "" {
+			endpoints[node.GetID()] = url
+		}
+	}
+	return endpoints
+}
+
+// ScrapeConfig renders a Prometheus configuration scraping every node with metrics enabled, labelled
+// with its node ID, and networkhub's own metrics served on hubAddr unless it is empty
+func (n *Network) ScrapeConfig(hubAddr string) string {
+	var b strings.Builder
+	b.WriteString("scrape_configs:\n")
+
+	if hubAddr != "" {
+		b.WriteString("  - job_name: networkhub\n")
+		b.WriteString("    static_configs:\n")
+		fmt.Fprintf(&b, "      - targets: [%q]\n", hubAddr)
+	}
+
+	var nodes strings.Builder
+	for _, node := range n.Nodes {
+		if node.GetMetricsURL() == "" {
+			continue
+		}
+		target := strings.ReplaceAll(node.GetMetricsAddr(), "0.0.0.0", "127.0.0.1")
+		fmt.Fprintf(&nodes, "      - targets: [%q]\n", target)
+		fmt.Fprintf(&nodes, "        labels:\n          node: %q\n", node.GetID())
+	}
+	if nodes.Len() > 0 {
+		b.WriteString("  - job_name: thor\n")
+		b.WriteString("    static_configs:\n")
+		b.WriteString(nodes.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,174 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Topology shapes, each one decides which nodes peer with each other
+const (
+	// TopologyFullMesh connects every node to every other node, the default
+	TopologyFullMesh = "full-mesh"
+	// TopologyLine connects each node to the previous and next node
+	TopologyLine = "line"
+	// TopologyRing is a line whose last node connects back to the first
+	TopologyRing = "ring"
+	// TopologyStar connects every node to a single hub
+	TopologyStar = "star"
+	// TopologyHubAndSpoke fully meshes the hubs and spreads the other nodes across them
+	TopologyHubAndSpoke = "hub-and-spoke"
+)
+
+// Topology declares which nodes of the network peer with each other.
+// Nodes follow the order of Network.Nodes. A named Shape and explicit Edges may be combined,
+// the edges are added on top of the shape. Peering is symmetric.
+type Topology struct {
+	Shape    string      `json:"shape,omitempty"`
+	Hubs     []string    `json:"hubs,omitempty"`     // hub nodes for star and hub-and-spoke, the first node by default
+	Edges    [][2]string `json:"edges,omitempty"`    // explicit node ID pairs that peer with each other
+	Isolated []string    `json:"isolated,omitempty"` // nodes with no peers at all, e.g. observers
+}
+
+// Peers returns the IDs of the nodes each node is expected to peer with, in network order.
+// Every node of ids has an entry, isolated nodes have none.
+func (t *Topology) Peers(ids []string) (map[string][]string, error) {
+	known := func(id string) error {
+		if !slices.Contains(ids, id) {
+			return fmt.Errorf("topology references unknown node %s", id)
+		}
+		return nil
+	}
+
+	for _, id := range append(slices.Clone(t.Hubs), t.Isolated...) {
+		if err := known(id); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range t.Hubs {
+		if slices.Contains(t.Isolated, id) {
+			return nil, fmt.Errorf("hub %s cannot be isolated", id)
+		}
+	}
+
+	// the shape only applies to connected nodes
+	var members []string
+	for _, id := range ids {
+		if !slices.Contains(t.Isolated, id) {
+			members = append(members, id)
+		}
+	}
+
+	linked := make(map[string]map[string]bool, len(ids))
+	for _, id := range ids {
+		linked[id] = make(map[string]bool)
+	}
+	link := func(a, b string) {
+		if a != b {
+			linked[a][b] = true
+			linked[b][a] = true
+		}
+	}
+
+	switch t.Shape {
+	case "", TopologyFullMesh:
+		// an explicit edge list with no shape only connects the listed pairs
+		if t.Shape == "" && len(t.Edges) > 0 {
+			break
+		}
+		for i := range members {
+			for j := i + 1; j < len(members); j++ {
+				link(members[i], members[j])
+			}
+		}
+	case TopologyLine, TopologyRing:
+		for i := 1; i < len(members); i++ {
+			link(members[i-1], members[i])
+		}
+		if t.Shape == TopologyRing && len(members) > 2 {
+			link(members[len(members)-1], members[0])
+		}
+	case TopologyStar, TopologyHubAndSpoke:
+		hubs := t.hubs(members)
+		if t.Shape == TopologyStar && len(hubs) > 1 {
+			return nil, fmt.Errorf("star topology takes a single hub, got %d", len(hubs))
+		}
+		for i := range hubs {
+			for j := i + 1; j < len(hubs); j++ {
+				link(hubs[i], hubs[j])
+			}
+		}
+		spoke := 0
+		for _, id := range members {
+			if slices.Contains(hubs, id) {
+				continue
+			}
+			link(id, hubs[spoke%len(hubs)])
+			spoke++
+		}
+	default:
+		return nil, fmt.Errorf("unknown topology shape %q", t.Shape)
+	}
+
+	for _, edge := range t.Edges {
+		for _, id := range edge {
+			if err := known(id); err != nil {
+				return nil, err
+			}
+			if slices.Contains(t.Isolated, id) {
+				return nil, fmt.Errorf("isolated node %s cannot have edges", id)
+			}
+		}
+		if edge[0] == edge[1] {
+			return nil, fmt.Errorf("node %s cannot peer with itself", edge[0])
+		}
+		link(edge[0], edge[1])
+	}
+
+	peers := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		peers[id] = []string{}
+		for _, other := range ids {
+			if linked[id][other] {
+				peers[id] = append(peers[id], other)
+			}
+		}
+	}
+	return peers, nil
+}
+
+// hubs returns the configured hubs, or the first member when none are set
+func (t *Topology) hubs(members []string) []string {
+	if len(t.Hubs) > 0 {
+		return t.Hubs
+	}
+	if len(members) > 0 {
+		return members[:1]
+	}
+	return nil
+}
+
+// ExpectedPeers returns the node IDs each node of the network is expected to peer with.
+// Without a topology every node peers with every other node, a partition drops the links between its groups.
+func (n *Network) ExpectedPeers() (map[string][]string, error) {
+	ids := make([]string, 0, len(n.Nodes))
+	for _, node := range n.Nodes {
+		ids = append(ids, node.GetID())
+	}
+
+	topology := n.Topology
+	if topology == nil {
+		topology = &Topology{Shape: TopologyFullMesh}
+	}
+	peers, err := topology.Peers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// A partition cuts every link between groups
+	if partition := n.Partition(); len(partition) > 0 {
+		for id, ids := range peers {
+			peers[id] = slices.DeleteFunc(ids, func(peer string) bool { return !samePartition(partition, id, peer) })
+		}
+	}
+	return peers, nil
+}
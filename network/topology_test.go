@@ -0,0 +1,82 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyPeers(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+
+	for _, tc := range []struct {
+		name     string
+		topology Topology
+		peers    map[string][]string
+	}{
+		{
+			name:     "FullMesh",
+			topology: Topology{},
+			peers: map[string][]string{
+				"a": {"b", "c", "d", "e"}, "b": {"a", "c", "d", "e"}, "c": {"a", "b", "d", "e"},
+				"d": {"a", "b", "c", "e"}, "e": {"a", "b", "c", "d"},
+			},
+		},
+		{
+			name:     "Line",
+			topology: Topology{Shape: TopologyLine},
+			peers:    map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"b", "d"}, "d": {"c", "e"}, "e": {"d"}},
+		},
+		{
+			name:     "Ring",
+			topology: Topology{Shape: TopologyRing},
+			peers:    map[string][]string{"a": {"b", "e"}, "b": {"a", "c"}, "c": {"b", "d"}, "d": {"c", "e"}, "e": {"a", "d"}},
+		},
+		{
+			name:     "Star",
+			topology: Topology{Shape: TopologyStar},
+			peers:    map[string][]string{"a": {"b", "c", "d", "e"}, "b": {"a"}, "c": {"a"}, "d": {"a"}, "e": {"a"}},
+		},
+		{
+			name:     "HubAndSpoke",
+			topology: Topology{Shape: TopologyHubAndSpoke, Hubs: []string{"a", "b"}},
+			peers:    map[string][]string{"a": {"b", "c", "e"}, "b": {"a", "d"}, "c": {"a"}, "d": {"b"}, "e": {"a"}},
+		},
+		{
+			name:     "Edges",
+			topology: Topology{Edges: [][2]string{{"a", "b"}, {"c", "b"}}},
+			peers:    map[string][]string{"a": {"b"}, "b": {"a", "c"}, "c": {"b"}, "d": {}, "e": {}},
+		},
+		{
+			name:     "IsolatedRing",
+			topology: Topology{Shape: TopologyRing, Isolated: []string{"e"}, Edges: [][2]string{{"a", "c"}}},
+			peers:    map[string][]string{"a": {"b", "c", "d"}, "b": {"a", "c"}, "c": {"a", "b", "d"}, "d": {"a", "c"}, "e": {}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			peers, err := tc.topology.Peers(ids)
+			require.NoError(t, err)
+			assert.Equal(t, tc.peers, peers)
+		})
+	}
+}
+
+func TestTopologyPeersErrors(t *testing.T) {
+	ids := []string{"a", "b", "c"}
+
+	for _, tc := range []struct {
+		topology Topology
+		err      string
+	}{
+		{Topology{Shape: "tree"}, `unknown topology shape "tree"`},
+		{Topology{Edges: [][2]string{{"a", "x"}}}, "unknown node x"},
+		{Topology{Edges: [][2]string{{"a", "a"}}}, "cannot peer with itself"},
+		{Topology{Isolated: []string{"a"}, Edges: [][2]string{{"a", "b"}}}, "isolated node a cannot have edges"},
+		{Topology{Shape: TopologyStar, Hubs: []string{"a", "b"}}, "single hub"},
+		{Topology{Shape: TopologyStar, Hubs: []string{"a"}, Isolated: []string{"a"}}, "hub a cannot be isolated"},
+	} {
+		_, err := tc.topology.Peers(ids)
+		assert.ErrorContains(t, err, tc.err)
+	}
+}
@@ -0,0 +1,137 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/httpclient"
+)
+
+// waitPollInterval is the time between checks of the nodes that have not reached the target yet
+const waitPollInterval = time.Second
+
+// NodeCondition checks whether a node has reached a target. It returns nil once reached, otherwise an error
+// saying why not, which is reported if the wait times out.
+type NodeCondition func(nodeCfg node.Config, client *thorclient.Client) error
+
+// WaitForAllNodes polls every node until each satisfies the condition or ctx is done.
+// Nodes are checked in parallel and not checked again once they have satisfied the condition.
+func (n *Network) WaitForAllNodes(ctx context.Context, condition NodeCondition) error {
+	if len(n.Nodes) == 0 {
+		return fmt.Errorf("no nodes defined in the network")
+	}
+
+	pending := make(map[string]error, len(n.Nodes))
+	clients := make(map[string]*thorclient.Client, len(n.Nodes))
+	for _, node := range n.Nodes {
+		pending[node.GetID()] = errors.New("not checked yet")
+		clients[node.GetID()] = thorclient.New(node.GetHTTPAddr())
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	// Check right away so nodes already at the target don't wait for the first tick
+	for {
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		for _, node := range n.Nodes {
+			if pending[node.GetID()] == nil {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := condition(node, clients[node.GetID()])
+				mu.Lock()
+				pending[node.GetID()] = err
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		var reasons []string
+		for _, node := range n.Nodes {
+			if err := pending[node.GetID()]; err != nil {
+				reasons = append(reasons, fmt.Sprintf("node %s: %v", node.GetID(), err))
+			}
+		}
+		if len(reasons) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for nodes - %s: %w", strings.Join(reasons, "; "), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForBlock waits until every node's best block is at least the given number
+func (n *Network) WaitForBlock(ctx context.Context, number uint32) error {
+	return n.WaitForAllNodes(ctx, func(_ node.Config, client *thorclient.Client) error {
+		return reached(client, "best", number)
+	})
+}
+
+// WaitForFinalized waits until every node has finalized the given block number
+func (n *Network) WaitForFinalized(ctx context.Context, number uint32) error {
+	return n.WaitForAllNodes(ctx, func(_ node.Config, client *thorclient.Client) error {
+		return reached(client, "finalized", number)
+	})
+}
+
+// WaitForTxReceipt waits until every node has included the transaction and returns its receipt.
+// A reverted transaction is not an error, check the receipt.
+func (n *Network) WaitForTxReceipt(ctx context.Context, id thor.Bytes32) (*api.Receipt, error) {
+	var (
+		mu      sync.Mutex
+		receipt *api.Receipt
+	)
+	err := n.WaitForAllNodes(ctx, func(_ node.Config, client *thorclient.Client) error {
+		nodeReceipt, err := client.TransactionReceipt(&id)
+		if errors.Is(err, httpclient.ErrNotFound) {
+			return fmt.Errorf("transaction %s not included yet", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if receipt == nil {
+			receipt = nodeReceipt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// reached checks the block at revision ("best" or "finalized") is at least number
+func reached(client *thorclient.Client, revision string, number uint32) error {
+	blk, err := client.Block(revision)
+	if err != nil {
+		return err
+	}
+	if blk == nil {
+		return fmt.Errorf("no %s block", revision)
+	}
+	if blk.Number < number {
+		return fmt.Errorf("%s block %d is below %d", revision, blk.Number, number)
+	}
+	return nil
+}
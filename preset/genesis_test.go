@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/network"
 	"github.com/vechain/thor/v2/genesis"
 )
 
@@ -25,3 +26,13 @@ func TestGenesisUnmarshal(t *testing.T) {
 	require.NoError(t, err)
 	t.Log(marshal)
 }
+
+func TestPresetsPassGenesisCheck(t *testing.T) {
+	validators, err := LocalValidatorsNetwork("presets", 20)
+	require.NoError(t, err)
+
+	for _, net := range []*network.Network{LocalThreeNodesNetwork(), LocalFourNodesHayabusa(), validators} {
+		_, err := net.CheckGenesis()
+		require.NoError(t, err, net.BaseID)
+	}
+}
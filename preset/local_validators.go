@@ -0,0 +1,76 @@
+package preset
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/vechain/networkhub/genesisbuilder"
+	"github.com/vechain/networkhub/internal/environments"
+	"github.com/vechain/networkhub/network"
+	"github.com/vechain/networkhub/network/node"
+	"github.com/vechain/networkhub/network/node/genesis"
+	thorgenesis "github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// maxLocalValidators keeps the p2p ports (8031+) below the API ports (8131+)
+const maxLocalValidators = 100
+
+// LocalValidatorsNetwork returns a local network of n validators whose keys are derived from the seed,
+// see genesisbuilder.DeriveValidators. Node i is named node<i+1>, listens for p2p on 8031+i and serves
+// the API on 127.0.0.1:8131+i.
+func LocalValidatorsNetwork(seed string, n int) (*network.Network, error) {
+	if n < 1 || n > maxLocalValidators {
+		return nil, fmt.Errorf("validator count must be between 1 and %d, got %d", maxLocalValidators, n)
+	}
+
+	validators := genesisbuilder.DeriveValidators(seed, n)
+	gen := LocalValidatorsNetworkGenesis(validators)
+
+	nodes := make([]node.Config, n)
+	for i, validator := range validators {
+		nodeCfg := validator.Node(fmt.Sprintf("node%d", i+1), gen)
+		nodeCfg.P2PListenPort = 8031 + i
+		nodeCfg.APIAddr = fmt.Sprintf("127.0.0.1:%d", 8131+i)
+		nodes[i] = nodeCfg
+	}
+
+	return &network.Network{
+		BaseID:      fmt.Sprintf("validators%d", n),
+		Environment: environments.Local,
+		Nodes:       nodes,
+	}, nil
+}
+
+// LocalValidatorsNetworkGenesis returns a genesis staking and funding the validators, with every validator
+// allowed to propose blocks
+func LocalValidatorsNetworkGenesis(validators []genesisbuilder.ValidatorKeys) *genesis.CustomGenesis {
+	hayabusaTP := uint32(0)
+	mbp := uint64(len(validators))
+
+	return genesisbuilder.New(len(validators)).
+		Validators(validators).
+		Params(thorgenesis.Params{
+			RewardRatio:         convToHexOrDecimal256(big.NewInt(300000000000000000)),
+			BaseGasPrice:        convToHexOrDecimal256(big.NewInt(1000000000000000)),
+			ProposerEndorsement: convToHexOrDecimal256(LargeBigValue),
+			MaxBlockProposers:   &mbp,
+		}).
+		ForkConfig(&genesis.CustomGenesisForkConfig{ForkConfig: thor.SoloFork}).
+		Config(&genesis.Config{
+			BlockInterval:              10,
+			EpochLength:                10,
+			SeederInterval:             10,
+			ValidatorEvictionThreshold: 40,
+			EvictionCheckInterval:      10,
+			LowStakingPeriod:           10,
+			MediumStakingPeriod:        20,
+			HighStakingPeriod:          40,
+			CooldownPeriod:             10,
+			HayabusaTP:                 &hayabusaTP,
+		}).
+		GasLimit(10_000_000).
+		GenesisTimestampDelay(5 * time.Second).
+		Build()
+}
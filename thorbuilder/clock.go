@@ -0,0 +1,216 @@
+package thorbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	// ClockOffsetEnv sets how far ahead the clock of a thor built with SkewableClock runs, as a Go duration
+	ClockOffsetEnv = "NETWORKHUB_CLOCK_OFFSET"
+	// ClockDriftEnv sets the seconds the clock of a thor built with SkewableClock gains per second
+	ClockDriftEnv = "NETWORKHUB_CLOCK_DRIFT"
+
+	// clockPackage is the package added to thor's module to read the skewed clock
+	clockPackage = "networkhubclock"
+)
+
+// clockSource is the clock package thor's time.Now, time.Since and time.Until calls are redirected to
+var clockSource = `// Package ` + clockPackage + ` is added by networkhub to skew the wall clock of thor
+package ` + clockPackage + `
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	start  = time.Now()
+	offset time.Duration
+	drift  float64
+)
+
+func init() {
+	var err error
+	if value := os.Getenv("` + ClockOffsetEnv + `"); value != "" {
+		if offset, err = time.ParseDuration(value); err != nil {
+			panic("invalid ` + ClockOffsetEnv + `: " + err.Error())
+		}
+	}
+	if value := os.Getenv("` + ClockDriftEnv + `"); value != "" {
+		if drift, err = strconv.ParseFloat(value, 64); err != nil {
+			panic("invalid ` + ClockDriftEnv + `: " + err.Error())
+		}
+	}
+}
+
+func Now() time.Time {
+	now := time.Now()
+	return now.Add(offset + time.Duration(float64(now.Sub(start))*drift))
+}
+
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+`
+
+// ClockSkewEnv returns the environment skewing the clock of a thor built with SkewableClock
+func ClockSkewEnv(offset time.Duration, drift float64) []string {
+	return []string{
+		fmt.Sprintf("%s=%s", ClockOffsetEnv, offset),
+		fmt.Sprintf("%s=%s", ClockDriftEnv, strconv.FormatFloat(drift, 'f', -1, 64)),
+	}
+}
+
+// writeClockOverlay writes a go build overlay into overlayDir that adds the clock package to the module in
+// srcDir and redirects the wall clock reads of its packages to it, leaving the source untouched.
+// It returns the path of the overlay file.
+func writeClockOverlay(srcDir, overlayDir string) (string, error) {
+	goMod, err := os.ReadFile(filepath.Join(srcDir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	modulePath := modfile.ModulePath(goMod)
+	if modulePath == "" {
+		return "", fmt.Errorf("no module path in %s", filepath.Join(srcDir, "go.mod"))
+	}
+
+	replace := make(map[string]string)
+	addFile := func(srcPath string, content []byte) error {
+		rel, err := filepath.Rel(srcDir, srcPath)
+		if err != nil {
+			return err
+		}
+		overlayPath := filepath.Join(overlayDir, rel)
+		if err := os.MkdirAll(filepath.Dir(overlayPath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(overlayPath, content, 0o644); err != nil {
+			return err
+		}
+		replace[srcPath] = overlayPath
+		return nil
+	}
+
+	clockImport := strconv.Quote(modulePath + "/" + clockPackage)
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != srcDir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			// Nested modules are not part of thor's module
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); path != srcDir && err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		patched, err := redirectClock(path, src, clockImport)
+		if err != nil {
+			return fmt.Errorf("failed to patch %s: %w", path, err)
+		}
+		if patched == nil {
+			return nil
+		}
+		return addFile(path, patched)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := addFile(filepath.Join(srcDir, clockPackage, "clock.go"), []byte(clockSource)); err != nil {
+		return "", err
+	}
+
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": replace})
+	if err != nil {
+		return "", err
+	}
+	overlayPath := filepath.Join(overlayDir, "overlay.json")
+	if err := os.WriteFile(overlayPath, overlay, 0o644); err != nil {
+		return "", err
+	}
+	return overlayPath, nil
+}
+
+// redirectClock rewrites the time.Now, time.Since and time.Until references of a Go file to the clock package,
+// keeping every line in place. It returns nil when the file does not read the clock.
+func redirectClock(path string, src []byte, clockImport string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renamed and dot imports are left alone
+	if !slices.ContainsFunc(file.Imports, func(spec *ast.ImportSpec) bool {
+		return spec.Path.Value == `"time"` && spec.Name == nil
+	}) {
+		return nil, nil
+	}
+
+	var offsets []int
+	ast.Inspect(file, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// Imported packages are left unresolved, unlike local variables named time
+		if x, ok := selector.X.(*ast.Ident); ok && x.Name == "time" && x.Obj == nil &&
+			slices.Contains([]string{"Now", "Since", "Until"}, selector.Sel.Name) {
+			offsets = append(offsets, fset.Position(x.Pos()).Offset)
+		}
+		return true
+	})
+	if len(offsets) == 0 {
+		return nil, nil
+	}
+
+	var lastImport ast.Decl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			lastImport = gen
+		}
+	}
+	last := fset.Position(lastImport.End()).Offset
+
+	patched := slices.Clone(src[:last])
+	patched = append(patched, "; import "+clockPackage+" "+clockImport...)
+	for _, offset := range offsets {
+		patched = append(patched, src[last:offset]...)
+		patched = append(patched, clockPackage...)
+		last = offset + len("time")
+	}
+	patched = append(patched, src[last:]...)
+	// The file may not use the time package anymore
+	patched = append(patched, "\nvar _ time.Duration\n"...)
+	return patched, nil
+}
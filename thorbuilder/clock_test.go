@@ -0,0 +1,76 @@
+package thorbuilder
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockOverlay(t *testing.T) {
+	srcDir := t.TempDir()
+	main := `package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	start := time.Now()
+	fmt.Println(start.Unix(), time.Since(start) < time.Second)
+}
+`
+	files := map[string]string{
+		"go.mod":           "module example.com/thor\n\ngo 1.22\n",
+		"main.go":          main,
+		"main_test.go":     "package main\n\nimport \"time\"\n\nvar _ = time.Now\n",
+		"format/format.go": "package format\n\nimport \"fmt\"\n\nfunc Format(v int) string { return fmt.Sprint(v) }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	overlayPath, err := writeClockOverlay(srcDir, t.TempDir())
+	require.NoError(t, err)
+	overlay, err := os.ReadFile(overlayPath)
+	require.NoError(t, err)
+	// Only the files reading the clock are replaced, tests are left out
+	assert.Contains(t, string(overlay), "main.go")
+	assert.NotContains(t, string(overlay), "main_test.go")
+	assert.NotContains(t, string(overlay), "format.go")
+
+	binary := filepath.Join(t.TempDir(), "thor")
+	build := exec.Command("go", "build", "-overlay", overlayPath, "-o", binary, ".")
+	build.Dir = srcDir
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	// The source is left untouched
+	source, err := os.ReadFile(filepath.Join(srcDir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, main, string(source))
+
+	run := exec.Command(binary)
+	run.Env = append(os.Environ(), ClockSkewEnv(time.Hour, 0.001)...)
+	out, err = run.Output()
+	require.NoError(t, err)
+	fields := strings.Fields(string(out))
+	require.Len(t, fields, 2)
+	now, err := strconv.ParseInt(fields[0], 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), now, 5)
+	assert.Equal(t, "true", fields[1])
+}
+
+func TestClockSkewEnv(t *testing.T) {
+	assert.Equal(t, []string{ClockOffsetEnv + "=-1.5s", ClockDriftEnv + "=0.001"}, ClockSkewEnv(-1500*time.Millisecond, 0.001))
+}
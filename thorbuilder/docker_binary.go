@@ -0,0 +1,216 @@
+package thorbuilder
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"debug/elf"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/vechain/networkhub/metrics"
+)
+
+// DefaultDockerBaseImage is the runtime image used to package a thor binary.
+// Binaries built with cgo link against glibc, so the base image needs a compatible libc.
+const DefaultDockerBaseImage = "ubuntu:24.04"
+
+// binaryDockerfile is the Dockerfile template used to package a thor binary into a runtime image
+const binaryDockerfile = `FROM %s
+COPY thor /usr/local/bin/thor
+WORKDIR /home/thor
+ENTRYPOINT ["thor"]
+`
+
+// BuildBinaryDockerImage builds (or reuses) the thor binary and packages it into a thin runtime image.
+// The same binary used by the local environment, including debug and race builds, can then run in Docker
+// without recompiling thor inside the image. A BinaryPath is packaged as is, without downloading thor.
+func (b *Builder) BuildBinaryDockerImage() (string, error) {
+	if b.config.BuildConfig == nil || b.config.BuildConfig.BinaryPath == "" {
+		if err := b.Download(); err != nil {
+			return "", fmt.Errorf("failed to download repository: %w", err)
+		}
+	}
+
+	binaryPath, err := b.Build()
+	if err != nil {
+		return "", fmt.Errorf("failed to build thor binary: %w", err)
+	}
+
+	return b.PackageDockerImage(binaryPath)
+}
+
+// PackageDockerImage packages the given thor binary into a runtime image.
+// Images are tagged by the hash of the binary and base image, and an existing image with that tag is reused.
+func (b *Builder) PackageDockerImage(binaryPath string) (string, error) {
+	defer metrics.ObserveBuild(metrics.BuildDockerPackage, time.Now())
+	baseImage := b.dockerBaseImage()
+
+	tag, err := binaryImageTag(binaryPath, baseImage)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute Docker image tag: %w", err)
+	}
+
+	ctx := context.Background()
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to query Docker daemon: %w", err)
+	}
+	if err := checkBinaryFormat(binaryPath, info.OSType, info.Architecture); err != nil {
+		return "", err
+	}
+
+	if _, err := cli.ImageInspect(ctx, tag); err == nil {
+		slog.Info("Reusing existing Docker image", "tag", tag)
+		return tag, nil
+	} else if !client.IsErrNotFound(err) {
+		return "", fmt.Errorf("failed to inspect Docker image: %w", err)
+	}
+
+	slog.Info("Packaging thor binary into Docker image", "tag", tag, "binary", binaryPath, "base", baseImage)
+	buildContext := binaryBuildContext([]byte(fmt.Sprintf(binaryDockerfile, baseImage)), binaryPath)
+	defer buildContext.Close()
+
+	resp, err := cli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  dockerfileName,
+		Remove:      true,
+		ForceRemove: true,
+		Labels: map[string]string{
+			"networkhub.binary-path": binaryPath,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build Docker image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := streamBuildOutput(resp.Body, tag); err != nil {
+		return "", fmt.Errorf("failed to build Docker image: %w", err)
+	}
+
+	slog.Info("Successfully packaged Docker image", "tag", tag)
+	return tag, nil
+}
+
+// dockerBaseImage returns the configured runtime base image or the default one
+func (b *Builder) dockerBaseImage() string {
+	if b.config.BuildConfig != nil && b.config.BuildConfig.DockerBaseImage != "" {
+		return b.config.BuildConfig.DockerBaseImage
+	}
+	return DefaultDockerBaseImage
+}
+
+// binaryImageTag returns the tag of the runtime image for the given binary and base image
+func binaryImageTag(binaryPath, baseImage string) (string, error) {
+	f, err := os.Open(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open thor binary: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	h.Write([]byte(baseImage))
+	h.Write([]byte{0})
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash thor binary: %w", err)
+	}
+
+	return fmt.Sprintf("%s:bin-%s", DockerImageRepository, hex.EncodeToString(h.Sum(nil))[:12]), nil
+}
+
+// elfArchitectures are the names Docker daemons report for the architectures of ELF binaries
+var elfArchitectures = map[elf.Machine][]string{
+	elf.EM_X86_64:  {"x86_64", "amd64"},
+	elf.EM_AARCH64: {"aarch64", "arm64"},
+	elf.EM_386:     {"i386", "i686", "386"},
+	elf.EM_ARM:     {"armv7l", "armv6l", "arm"},
+	elf.EM_RISCV:   {"riscv64"},
+	elf.EM_PPC64:   {"ppc64le", "ppc64"},
+	elf.EM_S390:    {"s390x"},
+}
+
+// checkBinaryFormat checks the thor binary is a linux ELF executable for the architecture of the Docker daemon,
+// so a binary built for the host (e.g. macOS) fails here rather than with exec format error in the container
+func checkBinaryFormat(binaryPath, osType, arch string) error {
+	if osType != "" && osType != "linux" {
+		return fmt.Errorf("docker daemon runs %s containers, thor binaries can only be packaged for linux", osType)
+	}
+
+	f, err := elf.Open(binaryPath)
+	if err != nil {
+		return fmt.Errorf("thor binary %s is not a linux ELF executable, build it with GOOS=linux: %w", binaryPath, err)
+	}
+	defer f.Close()
+
+	if f.OSABI != elf.ELFOSABI_NONE && f.OSABI != elf.ELFOSABI_LINUX {
+		return fmt.Errorf("thor binary %s is built for %s, not linux", binaryPath, f.OSABI)
+	}
+	if names, ok := elfArchitectures[f.Machine]; !ok || !slices.Contains(names, arch) {
+		return fmt.Errorf("thor binary %s is built for %s but the Docker daemon runs on %s", binaryPath, f.Machine, arch)
+	}
+	return nil
+}
+
+// binaryBuildContext streams a tar archive containing the Dockerfile and the thor binary
+func binaryBuildContext(dockerfile []byte, binaryPath string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := tw.WriteHeader(&tar.Header{
+			Name:    dockerfileName,
+			Mode:    0644,
+			Size:    int64(len(dockerfile)),
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = tw.Write(dockerfile)
+		}
+		if err == nil {
+			err = addBinaryEntry(tw, binaryPath)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// addBinaryEntry writes the thor binary to the tar writer as an executable named thor
+func addBinaryEntry(tw *tar.Writer, binaryPath string) error {
+	f, err := os.Open(binaryPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    "thor",
+		Mode:    0755,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,342 @@
+package thorbuilder
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/vechain/networkhub/metrics"
+)
+
+const (
+	// DockerImageRepository is the repository used to tag images built by the Builder
+	DockerImageRepository = "networkhub/thor"
+
+	// dockerfileName is the Dockerfile used from the thor repository root
+	dockerfileName = "Dockerfile"
+)
+
+// buildMessage is a single JSON message streamed back by the Docker build API
+type buildMessage struct {
+	Stream      string `json:"stream"`
+	Status      string `json:"status"`
+	ID          string `json:"id"`
+	Progress    string `json:"progress"`
+	Error       string `json:"error"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+	Aux *struct {
+		ID string `json:"ID"`
+	} `json:"aux"`
+}
+
+// BuildDockerImage builds a thor Docker image from the downloaded source using the Docker SDK.
+// Images are tagged by commit SHA plus a hash of the build configuration, so an image that
+// already exists with the same tag is reused instead of being rebuilt.
+func (b *Builder) BuildDockerImage() (string, error) {
+	if b.config.BuildConfig != nil && b.config.BuildConfig.SkewableClock {
+		return "", fmt.Errorf("the repository's Dockerfile cannot build a skewable clock, package the binary with DockerFromBinary")
+	}
+	defer metrics.ObserveBuild(metrics.BuildDockerImage, time.Now())
+	if err := b.Download(); err != nil {
+		return "", fmt.Errorf("failed to download repository: %w", err)
+	}
+
+	tag, err := b.DockerImageTag()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute Docker image tag: %w", err)
+	}
+
+	ctx := context.Background()
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	// Reuse the image if it was already built from the same source and configuration
+	if _, err := cli.ImageInspect(ctx, tag); err == nil {
+		slog.Info("Reusing existing Docker image", "tag", tag)
+		return tag, nil
+	} else if !client.IsErrNotFound(err) {
+		return "", fmt.Errorf("failed to inspect Docker image: %w", err)
+	}
+
+	slog.Info("Building Docker image", "tag", tag, "path", b.DownloadPath)
+	buildContext := tarDirectory(b.DownloadPath)
+	defer buildContext.Close()
+
+	resp, err := cli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  dockerfileName,
+		Remove:      true,
+		ForceRemove: true,
+		Labels: map[string]string{
+			"org.opencontainers.image.source": b.repoURL(),
+			"networkhub.build-path":           b.DownloadPath,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build Docker image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := streamBuildOutput(resp.Body, tag); err != nil {
+		return "", fmt.Errorf("failed to build Docker image: %w", err)
+	}
+
+	slog.Info("Successfully built Docker image", "tag", tag)
+	return tag, nil
+}
+
+// DockerImageTag returns the content-addressed tag for the downloaded source.
+// The tag has the form <repository>:<commit sha>-<config hash>.
+func (b *Builder) DockerImageTag() (string, error) {
+	sha, err := b.commitSHA()
+	if err != nil {
+		return "", err
+	}
+
+	configHash, err := b.buildConfigHash()
+	if err != nil {
+		return "", err
+	}
+
+	return dockerImageTag(sha, configHash), nil
+}
+
+// dockerImageTag formats the image tag from a commit SHA and a build config hash
+func dockerImageTag(sha, configHash string) string {
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	if len(configHash) > 12 {
+		configHash = configHash[:12]
+	}
+	return fmt.Sprintf("%s:%s-%s", DockerImageRepository, sha, configHash)
+}
+
+// commitSHA returns the HEAD commit of the downloaded repository
+func (b *Builder) commitSHA() (string, error) {
+	out, err := b.git("rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit SHA in %s: %w", b.DownloadPath, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// buildConfigHash hashes everything, other than the commit, that changes the resulting image:
+// the Dockerfile and any uncommitted changes in the working tree, including untracked files that
+// are part of the build context. The image is always built with the same options, so none are hashed.
+func (b *Builder) buildConfigHash() (string, error) {
+	dockerfile, err := os.ReadFile(filepath.Join(b.DownloadPath, dockerfileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dockerfileName, err)
+	}
+
+	diff, err := b.git("diff", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read working tree changes: %w", err)
+	}
+	status, err := b.git("status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return "", fmt.Errorf("failed to read working tree status: %w", err)
+	}
+	untracked, err := b.untrackedFiles()
+	if err != nil {
+		return "", err
+	}
+
+	return hashBuildInputs(dockerfileName, dockerfile, diff, status, untracked), nil
+}
+
+// untrackedFiles returns the untracked files of the build context with their contents, which git diff leaves out
+func (b *Builder) untrackedFiles() (string, error) {
+	out, err := b.git("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return "", fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	ignore, err := readDockerignore(b.DownloadPath)
+	if err != nil {
+		return "", err
+	}
+
+	var files strings.Builder
+	for _, name := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+		if name == "" {
+			continue
+		}
+		if excluded, err := ignore.excluded(name); err != nil {
+			return "", fmt.Errorf("failed to match %s against %s: %w", name, dockerignoreName, err)
+		} else if excluded {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(b.DownloadPath, filepath.FromSlash(name)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read untracked file %s: %w", name, err)
+		}
+		sum := sha256.Sum256(content)
+		fmt.Fprintf(&files, "%s %x\n", name, sum)
+	}
+	return files.String(), nil
+}
+
+// hashBuildInputs returns the hex encoded sha256 of the given build inputs
+func hashBuildInputs(dockerfileName string, dockerfile []byte, changes ...string) string {
+	h := sha256.New()
+	h.Write([]byte(dockerfileName))
+	h.Write([]byte{0})
+	h.Write(dockerfile)
+	for _, change := range changes {
+		h.Write([]byte{0})
+		h.Write([]byte(change))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// git runs a git command in the download path and returns its stdout
+func (b *Builder) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = b.DownloadPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// repoURL returns the configured repository URL, if any
+func (b *Builder) repoURL() string {
+	if b.config.DownloadConfig == nil {
+		return ""
+	}
+	return b.config.DownloadConfig.RepoUrl
+}
+
+// streamBuildOutput decodes the Docker build stream, logging progress and returning the first build error
+func streamBuildOutput(r io.Reader, tag string) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var msg buildMessage
+		if err := decoder.Decode(&msg); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode build output: %w", err)
+		}
+
+		switch {
+		case msg.ErrorDetail != nil:
+			return errors.New(msg.ErrorDetail.Message)
+		case msg.Error != "":
+			return errors.New(msg.Error)
+		case strings.TrimSpace(msg.Stream) != "":
+			slog.Info("docker build", "tag", tag, "output", strings.TrimSpace(msg.Stream))
+		case msg.Status != "":
+			slog.Debug("docker build", "tag", tag, "status", msg.Status, "id", msg.ID, "progress", msg.Progress)
+		case msg.Aux != nil && msg.Aux.ID != "":
+			slog.Info("docker build", "tag", tag, "imageID", msg.Aux.ID)
+		}
+	}
+}
+
+// tarDirectory streams the given directory as a tar archive, skipping the .git and bin directories and
+// the paths its .dockerignore excludes, like the Docker CLI does
+func tarDirectory(dir string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		ignore, err := readDockerignore(dir)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		// Rules re-including paths may match inside excluded directories, so those are walked too
+		hasIncludes := ignore.hasIncludes()
+
+		tw := tar.NewWriter(pw)
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+			if d.IsDir() && (rel == ".git" || rel == "bin") {
+				return filepath.SkipDir
+			}
+			name := filepath.ToSlash(rel)
+			// The Docker daemon needs the Dockerfile and .dockerignore even when they are ignored
+			if name != dockerfileName && name != dockerignoreName {
+				excluded, err := ignore.excluded(name)
+				if err != nil {
+					return err
+				}
+				if excluded {
+					if d.IsDir() && !hasIncludes {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+			return addTarEntry(tw, path, name, d)
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// addTarEntry writes a single file, directory or symlink to the tar writer
+func addTarEntry(tw *tar.Writer, path, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,197 @@
+package thorbuilder
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/networkhub/metrics"
+)
+
+func TestDockerImageTag(t *testing.T) {
+	// Set up a minimal git repository with a Dockerfile
+	repoPath := t.TempDir()
+	runGit := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	runGit("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "Dockerfile"), []byte("FROM scratch\n"), 0644))
+	runGit("add", "Dockerfile")
+	runGit("-c", "user.name=test", "-c", "user.email=test@test", "commit", "-q", "-m", "init")
+
+	builder := New(&Config{BuildConfig: &BuildConfig{ExistingPath: repoPath}})
+
+	t.Run("Tag is stable for the same source", func(t *testing.T) {
+		tag1, err := builder.DockerImageTag()
+		require.NoError(t, err)
+		tag2, err := builder.DockerImageTag()
+		require.NoError(t, err)
+
+		assert.Equal(t, tag1, tag2)
+		assert.True(t, strings.HasPrefix(tag1, DockerImageRepository+":"))
+	})
+
+	t.Run("Tag changes with uncommitted changes", func(t *testing.T) {
+		clean, err := builder.DockerImageTag()
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, "Dockerfile"), []byte("FROM busybox\n"), 0644))
+		dirty, err := builder.DockerImageTag()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, clean, dirty)
+	})
+
+	t.Run("Tag changes with untracked files", func(t *testing.T) {
+		before, err := builder.DockerImageTag()
+		require.NoError(t, err)
+
+		newFile := filepath.Join(repoPath, "cmd", "new.go")
+		require.NoError(t, os.MkdirAll(filepath.Dir(newFile), 0755))
+		require.NoError(t, os.WriteFile(newFile, []byte("package main\n"), 0644))
+		added, err := builder.DockerImageTag()
+		require.NoError(t, err)
+		assert.NotEqual(t, before, added)
+
+		require.NoError(t, os.WriteFile(newFile, []byte("package main\n\nfunc main() {}\n"), 0644))
+		edited, err := builder.DockerImageTag()
+		require.NoError(t, err)
+		assert.NotEqual(t, added, edited)
+	})
+
+	t.Run("Missing repository", func(t *testing.T) {
+		builder := New(&Config{BuildConfig: &BuildConfig{ExistingPath: t.TempDir()}})
+		_, err := builder.DockerImageTag()
+		assert.Error(t, err)
+	})
+}
+
+func TestTarDirectoryDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Dockerfile":         "FROM scratch\n",
+		".dockerignore":      "# comments are skipped\nDockerfile\ndocs\n**/*.log\nbuild/*\n!build/keep.txt\ntmp[0-9]\n",
+		"main.go":            "package main\n",
+		"docs/readme.md":     "docs\n",
+		"api/debug.log":      "log\n",
+		"build/out.bin":      "bin\n",
+		"build/keep.txt":     "keep\n",
+		"thor/consensus.log": "log\n",
+		"tmp1/scratch.txt":   "scratch\n",
+		"tmpa/kept.txt":      "kept\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	archive := tarDirectory(dir)
+	defer archive.Close()
+	var names []string
+	tr := tar.NewReader(archive)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"Dockerfile", ".dockerignore", "main.go", "build/keep.txt", "tmpa/kept.txt"}, names)
+}
+
+func TestBinaryImageTag(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "thor")
+	require.NoError(t, os.WriteFile(binaryPath, []byte("thor-binary-v1"), 0755))
+
+	tag1, err := binaryImageTag(binaryPath, DefaultDockerBaseImage)
+	require.NoError(t, err)
+	tag2, err := binaryImageTag(binaryPath, DefaultDockerBaseImage)
+	require.NoError(t, err)
+	assert.Equal(t, tag1, tag2)
+	assert.True(t, strings.HasPrefix(tag1, DockerImageRepository+":bin-"))
+
+	// A different base image results in a different image
+	otherBase, err := binaryImageTag(binaryPath, "debian:bookworm-slim")
+	require.NoError(t, err)
+	assert.NotEqual(t, tag1, otherBase)
+
+	// A different binary results in a different image
+	require.NoError(t, os.WriteFile(binaryPath, []byte("thor-binary-v2"), 0755))
+	otherBinary, err := binaryImageTag(binaryPath, DefaultDockerBaseImage)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag1, otherBinary)
+}
+
+func TestCheckBinaryFormat(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("needs a linux ELF binary")
+	}
+	// The running test binary is an ELF for the host architecture
+	self, err := os.Executable()
+	require.NoError(t, err)
+	arch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if arch == "" {
+		t.Skipf("no daemon architecture name for %s", runtime.GOARCH)
+	}
+
+	assert.NoError(t, checkBinaryFormat(self, "linux", arch))
+	assert.NoError(t, checkBinaryFormat(self, "linux", runtime.GOARCH))
+
+	other := map[string]string{"x86_64": "aarch64", "aarch64": "x86_64"}[arch]
+	assert.ErrorContains(t, checkBinaryFormat(self, "linux", other), "but the Docker daemon runs on "+other)
+	assert.ErrorContains(t, checkBinaryFormat(self, "windows", arch), "can only be packaged for linux")
+
+	script := filepath.Join(t.TempDir(), "thor")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho thor\n"), 0755))
+	assert.ErrorContains(t, checkBinaryFormat(script, "linux", arch), "is not a linux ELF executable")
+}
+
+func TestBuildWithBinaryPath(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "thor")
+	require.NoError(t, os.WriteFile(binaryPath, []byte("thor"), 0755))
+
+	builds := binaryBuilds(t)
+	builder := New(&Config{BuildConfig: &BuildConfig{BinaryPath: binaryPath}})
+	path, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, binaryPath, path)
+	// Nothing was compiled, so no build is recorded
+	assert.Equal(t, builds, binaryBuilds(t))
+
+	builder = New(&Config{BuildConfig: &BuildConfig{BinaryPath: filepath.Join(t.TempDir(), "missing")}})
+	_, err = builder.Build()
+	assert.Error(t, err)
+}
+
+// binaryBuilds returns the number of thor binary builds recorded in the metrics
+func binaryBuilds(t *testing.T) uint64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "networkhub_build_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "kind" && label.GetValue() == metrics.BuildBinary {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
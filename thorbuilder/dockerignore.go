@@ -0,0 +1,56 @@
+package thorbuilder
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// dockerignoreName is the file listing the paths left out of the build context
+const dockerignoreName = ".dockerignore"
+
+// dockerignore matches paths against the rules of a .dockerignore file with the Docker CLI's own matcher.
+// A nil dockerignore excludes nothing.
+type dockerignore struct {
+	matcher *patternmatcher.PatternMatcher
+}
+
+// readDockerignore reads the .dockerignore of the directory, none is a nil dockerignore
+func readDockerignore(dir string) (*dockerignore, error) {
+	f, err := os.Open(filepath.Join(dir, dockerignoreName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dockerignoreName, err)
+	}
+	defer f.Close()
+
+	patterns, err := ignorefile.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dockerignoreName, err)
+	}
+	matcher, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", dockerignoreName, err)
+	}
+	return &dockerignore{matcher: matcher}, nil
+}
+
+// excluded tells whether the slash separated path, relative to the build context, is left out of it
+func (d *dockerignore) excluded(rel string) (bool, error) {
+	if d == nil {
+		return false, nil
+	}
+	return d.matcher.MatchesOrParentMatches(filepath.FromSlash(rel))
+}
+
+// hasIncludes tells whether rules starting with ! re-include paths excluded by earlier rules
+func (d *dockerignore) hasIncludes() bool {
+	return d != nil && d.matcher.Exclusions()
+}
@@ -10,6 +10,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"time"
+
+	"github.com/vechain/networkhub/metrics"
 )
 
 type Config struct {
@@ -25,7 +28,18 @@ type DownloadConfig struct {
 type BuildConfig struct {
 	ExistingPath string
 	DebugBuild   bool
-	ReuseBinary  bool
+	// RaceBuild builds thor with the race detector, which needs cgo and a glibc runtime image when packaged
+	RaceBuild bool
+	// SkewableClock builds thor reading its wall clock through a clock that ClockOffsetEnv and ClockDriftEnv skew,
+	// which nodes with a clock skew need. The binary is built next to the regular one, as bin/thor-skewable.
+	SkewableClock bool
+	ReuseBinary   bool
+	// BinaryPath points to an already built thor binary (e.g. a downloaded release) that is used instead of building
+	BinaryPath string
+	// DockerFromBinary packages the thor binary into a thin runtime image instead of building the repository's Dockerfile
+	DockerFromBinary bool
+	// DockerBaseImage is the runtime image the binary is packaged into (defaults to DefaultDockerBaseImage)
+	DockerBaseImage string
 }
 
 type Builder struct {
@@ -152,12 +166,28 @@ func (b *Builder) Download() error {
 
 // Build runs the make command in the downloadPath and returns the path to the thor binary.
 func (b *Builder) Build() (string, error) {
+	// Use the prebuilt binary when one is provided
+	if b.config.BuildConfig != nil && b.config.BuildConfig.BinaryPath != "" {
+		if b.config.BuildConfig.SkewableClock {
+			return "", fmt.Errorf("a prebuilt thor binary cannot be built with a skewable clock")
+		}
+		if _, err := os.Stat(b.config.BuildConfig.BinaryPath); err != nil {
+			return "", fmt.Errorf("thor binary not found at configured path: %w", err)
+		}
+		slog.Info("Using prebuilt Thor binary", "path", b.config.BuildConfig.BinaryPath)
+		return b.config.BuildConfig.BinaryPath, nil
+	}
+
 	if _, err := os.Stat(b.DownloadPath); os.IsNotExist(err) {
 		return "", fmt.Errorf("download directory does not exist: %s", b.DownloadPath)
 	}
 
 	// Check if we should reuse existing binary
-	thorBinaryPath := filepath.Join(b.DownloadPath, "bin", "thor")
+	binaryName := "thor"
+	if b.config.BuildConfig != nil && b.config.BuildConfig.SkewableClock {
+		binaryName = "thor-skewable"
+	}
+	thorBinaryPath := filepath.Join(b.DownloadPath, "bin", binaryName)
 	if b.config.BuildConfig != nil && b.config.BuildConfig.ReuseBinary {
 		if _, err := os.Stat(thorBinaryPath); err == nil {
 			slog.Info("Reusing existing Thor binary", "path", thorBinaryPath)
@@ -165,17 +195,41 @@ func (b *Builder) Build() (string, error) {
 		}
 	}
 
+	defer metrics.ObserveBuild(metrics.BuildBinary, time.Now())
 	var cmd *exec.Cmd
 
-	if b.config.BuildConfig != nil && b.config.BuildConfig.DebugBuild {
-		cmd = exec.Command(
-			"go", "build",
-			"-gcflags=all=-N -l", // Disable optimizations. Useful for debugging.
+	if buildCfg := b.config.BuildConfig; buildCfg != nil && (buildCfg.DebugBuild || buildCfg.RaceBuild || buildCfg.SkewableClock) {
+		args := []string{"build"}
+		if buildCfg.DebugBuild {
+			args = append(args, "-gcflags=all=-N -l") // Disable optimizations. Useful for debugging.
+		}
+		if buildCfg.RaceBuild {
+			args = append(args, "-race")
+		}
+		if buildCfg.SkewableClock {
+			// The clock is patched in through an overlay, the downloaded source stays as is
+			overlayDir, err := os.MkdirTemp("", "thor_clock_overlay_")
+			if err != nil {
+				return "", fmt.Errorf("failed to create clock overlay dir: %w", err)
+			}
+			defer os.RemoveAll(overlayDir)
+			overlayPath, err := writeClockOverlay(b.DownloadPath, overlayDir)
+			if err != nil {
+				return "", fmt.Errorf("failed to patch the thor clock: %w", err)
+			}
+			args = append(args, "-overlay", overlayPath)
+		}
+		args = append(args,
 			"-v",
-			"-o", "./bin/thor",
+			"-o", "./bin/"+binaryName,
 			"-ldflags", "-X main.version=0.0.0 -X main.gitCommit=sha -X main.gitTag=v0.0.0 -X main.copyrightYear=2025",
 			"./cmd/thor",
 		)
+		cmd = exec.Command("go", args...)
+		// The race detector is only available with cgo
+		if buildCfg.RaceBuild {
+			cmd.Env = append(os.Environ(), "CGO_ENABLED=1")
+		}
 	} else {
 		cmd = exec.Command("make")
 	}
@@ -204,26 +258,6 @@ func (b *Builder) Build() (string, error) {
 	return thorBinaryPath, nil
 }
 
-func (b *Builder) BuildDockerImage() (string, error) {
-	if err := b.Download(); err != nil {
-		return "", fmt.Errorf("failed to download repository: %w", err)
-	}
-
-	tag := fmt.Sprintf("test_%s_%s", b.config.DownloadConfig.Branch, generateRandomSuffix(4))
-
-	// Build the Docker image
-	cmd := exec.Command("docker", "build", "-t", tag, b.DownloadPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to build Docker image: %w", err)
-	}
-
-	slog.Info("Successfully built Docker image", "tag", tag)
-	return tag, nil
-}
-
 // isCommitSHA checks if the given string looks like a Git commit SHA.
 // It returns true if the string is 7-40 characters long and contains only hexadecimal characters.
 func isCommitSHA(ref string) bool {
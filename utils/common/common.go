@@ -2,7 +2,7 @@ package common
 
 import (
 	"crypto/ecdsa"
-	"fmt"
+	"encoding/hex"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -41,7 +41,7 @@ type Account struct {
 }
 
 func (a Account) PrivateKeyString() string {
-	return fmt.Sprintf("%x", a.PrivateKey.D.Bytes()) //nolint:staticcheck
+	return hex.EncodeToString(crypto.FromECDSA(a.PrivateKey))
 }
 
 type TxSendResult struct {